@@ -2,108 +2,21 @@ package main
 
 import (
 	"html/template"
+	htmlgenerator "pipeline-html-generator/internal/generators"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-const htmlTemplate = `
-<html>
-<head>
-    <style>
-        body {
-            font-family: Arial, sans-serif;
-            margin: 0;
-            background-color: #f0f0f0;
-        }
-        .header {
-            background-color: #0B5ED7; 
-            color: white; 
-            padding: 20px; 
-            text-align: center; 
-            font-size: 24px;
-            border-bottom: 2px solid #fff;
-        } 
-        .section {
-            padding: 20px;
-            background-color: #fff;
-            margin: 10px 20px;
-            border-radius: 8px;
-            box-shadow: 0 0 10px rgba(0,0,0,0.1);
-        }
-        .green {
-            background-color: rgba(40, 167, 69, 0.1);
-        }
-        .red {
-            background-color: rgba(203, 36, 49, 0.1);
-        }
-        .orange {
-            background-color: rgba(227, 98, 9, 0.1);
-        }
-        table {
-            width: 100%; 
-            border-collapse: collapse; 
-            margin-bottom: 20px;
-        }
-        th, td {
-            border: 1px solid #ccc; 
-            padding: 8px; 
-            text-align: left; 
-        }
-        th {
-            background-color: #f8f8f8;
-        }
-    </style>
-</head>
-<body>
-    <div class="header">
-        Commit Insights Report
-    </div>
-    <div class="section">
-        <strong>Repository Name:</strong> {{.RepoName}}<br>
-        <strong>Branch Name:</strong> {{.BranchName}}<br>
-        <strong>Trigger Type:</strong> {{.TriggerType}}<br>
-    </div>
-    <div class="section">
-        <strong>Committers:</strong> {{.Committers}}
-    </div>
-    <div class="section">
-        <strong>Participants:</strong> {{.Participants}}
-    </div>
-    <div class="section">
-        <strong>File Changes:</strong>
-        <table>
-            <tr>
-                <th>Committer/Reviewer</th>
-                <th>Status</th>
-                <th>File Name</th>
-                <th>Commit Hash</th>
-                <th>Title</th>
-                <th>Date</th>
-            </tr>
-            {{range .FileChanges}}
-            <tr class="{{.StatusClass}}">
-                <td>{{.Committer}}{{if .Reviewer}} / {{.Reviewer}}{{end}}</td>
-                <td>{{.Status}}</td>
-                <td>{{.FileName}}</td>
-                <td>{{.CommitHash}}</td>
-                <td>{{.Title}}</td>
-                <td>{{.Time}}</td>
-            </tr>
-            {{end}}
-        </table>
-    </div>
-</body>
-</html>
-`
-
+// reportData is the data contract handed to the commit insights template.
 type reportData struct {
 	RepoName     string
 	BranchName   string
 	TriggerType  string
 	Committers   string
 	Participants string
+	Theme        htmlgenerator.Theme
 	FileChanges  []struct {
 		FileName    string
 		Status      string
@@ -124,7 +37,7 @@ func GenerateReport(repoName string, branchName string, serviceName string, comm
 	CommitHash string
 	Title      string
 	Time       string
-}) (string, error) {
+}, opts htmlgenerator.Options) (string, error) {
 	var committersStr, participantsStr string
 	if len(committers) > 0 {
 		committersStr = strings.Join(committers, ", ")
@@ -197,6 +110,7 @@ func GenerateReport(repoName string, branchName string, serviceName string, comm
 		BranchName:   branchName,
 		Committers:   committersStr,
 		Participants: participantsStr,
+		Theme:        opts.ReportTheme(),
 		FileChanges: func() []struct {
 			FileName    string
 			Status      string
@@ -242,7 +156,7 @@ func GenerateReport(repoName string, branchName string, serviceName string, comm
 		}(),
 	}
 
-	tmpl, err := template.New("report").Parse(htmlTemplate)
+	tmpl, err := htmlgenerator.LoadTemplate(htmlgenerator.CommitReportTemplate, opts)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"pipeline-html-generator/internal/analysis"
+	htmlgenerator "pipeline-html-generator/internal/generators"
+	"pipeline-html-generator/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDoraOutputs are written when the dora command gets no --output.
+var defaultDoraOutputs = []string{"html=dora.html", "json=dora.json", "markdown=dora.md"}
+
+// doraRenderers are the formats supported by the dora command, with their default file names.
+var doraRenderers = map[string]struct {
+	filename string
+	render   func(report models.DoraReport, opts htmlgenerator.Options) ([]byte, error)
+}{
+	"html": {"dora.html", func(report models.DoraReport, opts htmlgenerator.Options) ([]byte, error) {
+		output, err := htmlgenerator.GenerateDoraHTML(report, opts)
+		return []byte(output), err
+	}},
+	"json": {"dora.json", func(report models.DoraReport, opts htmlgenerator.Options) ([]byte, error) {
+		return htmlgenerator.GenerateDoraJSON(report)
+	}},
+	"markdown": {"dora.md", func(report models.DoraReport, opts htmlgenerator.Options) ([]byte, error) {
+		output, err := htmlgenerator.GenerateDoraMarkdown(report, opts)
+		return []byte(output), err
+	}},
+}
+
+// ExecDora computes the DORA metrics of the configured deployment pipeline and writes the reports.
+func (p *Plugin) ExecDora() error {
+	plugin = *p
+
+	if err := checkOutputVarsPrefix(p.Config.OutputVarsPrefix); err != nil {
+		return err
+	}
+
+	windowDays := p.Config.DoraWindowDays
+	if windowDays <= 0 {
+		windowDays = 30
+	}
+	windowEnd := time.Now()
+	windowStart := windowEnd.AddDate(0, 0, -windowDays)
+
+	fmt.Println(lineBreak)
+	fmt.Printf("| \033[1;36mComputing DORA metrics over the last\033[0m \033[1;32m%d\033[0m days\n", windowDays)
+	fmt.Println(lineBreak)
+
+	summaries, err := windowSummaries(p.Config, windowStart)
+	if err != nil {
+		return err
+	}
+
+	var deployments []models.Deployment
+	for _, summary := range summaries {
+		deployments = append(deployments, deploymentFromSummary(summary))
+	}
+
+	report := analysis.Dora(deployments, windowStart, windowEnd)
+	report.PipelineId = p.Config.PipelineID
+	report.PipelineName = p.Config.PipelineID
+	if len(summaries) > 0 {
+		report.PipelineName = summaries[0].Name
+	}
+
+	fmt.Printf("| \033[1;36mDeployment frequency:\033[0m \033[1;32m%.2f/day (%s)\033[0m\n", report.DeploymentsPerDay, report.DeploymentFrequency)
+	fmt.Printf("| \033[1;36mLead time:\033[0m \033[1;32m%s (%s)\033[0m\n", time.Duration(report.LeadTimeMedianMs)*time.Millisecond, report.LeadTime)
+	fmt.Printf("| \033[1;36mChange failure rate:\033[0m \033[1;32m%.1f%% (%s)\033[0m\n", report.ChangeFailureRate, report.ChangeFailure)
+	fmt.Printf("| \033[1;36mTime to restore:\033[0m \033[1;32m%s (%s)\033[0m\n", time.Duration(report.RestoreMedianMs)*time.Millisecond, report.TimeToRestore)
+	fmt.Println(lineBreak)
+
+	theme, err := htmlgenerator.LoadTheme(p.Config.Theme, p.Config.ThemeFile)
+	if err != nil {
+		return err
+	}
+	renderOptions := htmlgenerator.Options{TemplateDir: p.Config.TemplateDir, Theme: theme}
+
+	if err := writeDoraOutputs(report, renderOptions, p.Config.Outputs, p.Config.OutputDir); err != nil {
+		return err
+	}
+
+	vars := map[string]string{
+		"DORA_DEPLOYMENT_FREQUENCY": strconv.FormatFloat(report.DeploymentsPerDay, 'f', 2, 64),
+		"DORA_LEAD_TIME_MS":         strconv.FormatInt(report.LeadTimeMedianMs, 10),
+		"DORA_CHANGE_FAILURE_RATE":  strconv.FormatFloat(report.ChangeFailureRate, 'f', 1, 64),
+		"DORA_TIME_TO_RESTORE_MS":   strconv.FormatInt(report.RestoreMedianMs, 10),
+	}
+	return writeOutputVars(p.Config, vars)
+}
+
+// windowSummaries pages through the executions of the pipeline, most recent
+// first, until the oldest one started before windowStart. The CI repository
+// and branch filter of the report does not apply to deployment pipelines.
+func windowSummaries(config Config, windowStart time.Time) ([]Content, error) {
+	pageSize := config.DoraPageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	var summaries []Content
+	seen := map[string]bool{}
+	for page := 0; ; page++ {
+		content, err := getExecutionSummariesPage(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, trendStatusList, "", "", page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range content {
+			// Executions finishing while paging shift the pages, so one can show up twice.
+			if !seen[summary.PlanExecutionId] {
+				seen[summary.PlanExecutionId] = true
+				summaries = append(summaries, summary)
+			}
+		}
+		if len(content) < pageSize || int64(content[len(content)-1].StartTs) < windowStart.UnixMilli() {
+			return summaries, nil
+		}
+	}
+}
+
+// deploymentFromSummary reads the outcome and shipped commits of an execution summary.
+func deploymentFromSummary(content Content) models.Deployment {
+	deployment := models.Deployment{
+		ExecutionId: content.PlanExecutionId,
+		Status:      content.Status,
+		StartMs:     int64(content.StartTs),
+		EndMs:       int64(content.EndTs),
+	}
+	if deployment.EndMs > deployment.StartMs {
+		deployment.DurationMs = deployment.EndMs - deployment.StartMs
+	}
+	for _, commit := range content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits {
+		deployment.Commits = append(deployment.Commits, models.Commit{Sha: commit.ID, Message: commit.Message, TimestampMs: commit.TimeStamp})
+	}
+	return deployment
+}
+
+// writeDoraOutputs writes the DORA report in every "format=path" entry of outputs.
+func writeDoraOutputs(report models.DoraReport, opts htmlgenerator.Options, outputs []string, outputDir string) error {
+	if len(outputs) == 0 {
+		outputs = defaultDoraOutputs
+	}
+
+	for _, entry := range outputs {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		format, path, _ := strings.Cut(entry, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		renderer, ok := doraRenderers[format]
+		if !ok {
+			return fmt.Errorf("unknown dora output format %q, available formats: html, json, markdown", format)
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			path = renderer.filename
+		}
+		if outputDir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(outputDir, path)
+		}
+
+		content, err := renderer.render(report, opts)
+		if err != nil {
+			return fmt.Errorf("error rendering dora %s output: %w", format, err)
+		}
+		if err := createDirIfNotExists(path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+
+		fmt.Println(lineBreak)
+		fmt.Printf("| \033[1;36mDORA %s report saved to %s\033[0m\n", format, path)
+		fmt.Println(lineBreak)
+	}
+	return nil
+}
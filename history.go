@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"pipeline-html-generator/internal/analysis"
+	"pipeline-html-generator/internal/models"
+)
+
+// defaultCompareStatusList compares against the last green execution.
+var defaultCompareStatusList = []string{"Success"}
+
+// getPreviousExecution returns the most recent execution of the same pipeline,
+// branch and repository that started before current and matches statusList.
+func getPreviousExecution(config Config, current models.Pipeline, statusList []string, depth int) (models.Pipeline, error) {
+	if len(statusList) == 0 {
+		statusList = defaultCompareStatusList
+	}
+	if depth < 2 {
+		depth = 2
+	}
+
+	fmt.Println(lineBreak)
+	fmt.Printf("| \033[1;36mSearching previous execution with status:\033[0m \033[1;32m%s\033[0m\n", statusList)
+	fmt.Println(lineBreak)
+
+	executions, err := getExecutionSummaries(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, statusList, config.RepoName, config.Branch, depth)
+	if err != nil {
+		return models.Pipeline{}, err
+	}
+
+	for _, execution := range executions {
+		if execution.PlanExecutionId == current.ExecutionId || (current.StartMs > 0 && int64(execution.StartTs) >= current.StartMs) {
+			continue
+		}
+		previous, err := buildPipeline(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, execution)
+		if err != nil {
+			return models.Pipeline{}, err
+		}
+		previous.ExecutionLink = buildExecutionLink(config, previous.ExecutionId)
+		return previous, nil
+	}
+
+	return models.Pipeline{}, errors.New("no previous execution found")
+}
+
+// flakyStatusList covers the terminal statuses that tell whether steps passed or failed.
+var flakyStatusList = []string{"Success", "Failed", "Errored", "IgnoreFailed", "Expired"}
+
+// getRecentExecutions returns up to depth recent executions of the same pipeline,
+// branch and repository with their stages and steps, including current.
+func getRecentExecutions(config Config, current models.Pipeline, depth int) ([]models.Pipeline, error) {
+	if depth < 2 {
+		depth = 2
+	}
+
+	fmt.Println(lineBreak)
+	fmt.Printf("| \033[1;36mFetching recent executions:\033[0m \033[1;32m%d\033[0m\n", depth)
+	fmt.Println(lineBreak)
+
+	summaries, err := getExecutionSummaries(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, flakyStatusList, config.RepoName, config.Branch, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	executions := []models.Pipeline{current}
+	for _, summary := range summaries {
+		if summary.PlanExecutionId == current.ExecutionId {
+			continue
+		}
+		if len(executions) == depth {
+			break
+		}
+		execution, err := buildPipeline(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, summary)
+		if err != nil {
+			return nil, err
+		}
+		execution.ExecutionLink = buildExecutionLink(config, execution.ExecutionId)
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+// trendStatusList covers every terminal status so the trend shows failures as well as successes.
+var trendStatusList = []string{"Success", "Failed", "Errored", "IgnoreFailed", "Aborted", "Expired", "ApprovalRejected"}
+
+// getTrend returns the durations and outcomes of up to depth recent executions,
+// including current. Only the execution summaries are fetched.
+func getTrend(config Config, current models.Pipeline, depth int) (models.Trend, error) {
+	if depth < 2 {
+		depth = 2
+	}
+
+	fmt.Println(lineBreak)
+	fmt.Printf("| \033[1;36mFetching execution trend:\033[0m \033[1;32m%d\033[0m\n", depth)
+	fmt.Println(lineBreak)
+
+	summaries, err := getExecutionSummaries(config.AccID, config.OrgID, config.ProjectID, config.PipelineID, trendStatusList, config.RepoName, config.Branch, depth)
+	if err != nil {
+		return models.Trend{}, err
+	}
+
+	points := []models.TrendPoint{currentTrendPoint(current)}
+	for _, summary := range summaries {
+		if summary.PlanExecutionId == current.ExecutionId {
+			continue
+		}
+		if len(points) == depth {
+			break
+		}
+		points = append(points, trendPoint(summary))
+	}
+
+	return analysis.NewTrend(points), nil
+}
+
+// trendPoint reads the total and per-stage durations of an execution summary.
+// Only the nodes listed as stages in the report are counted.
+func trendPoint(content Content) models.TrendPoint {
+	point := models.TrendPoint{
+		ExecutionId:      content.PlanExecutionId,
+		Status:           content.Status,
+		StartMs:          int64(content.StartTs),
+		DurationMs:       graphEndMs(int64(content.StartTs), int64(content.EndTs)) - int64(content.StartTs),
+		StageDurationsMs: map[string]int64{},
+	}
+	for _, node := range content.LayoutNodeMap {
+		// the same stages as buildPipeline, so the series match currentTrendPoint
+		if !isReportedStage(node) || node.StartTs == 0 {
+			continue
+		}
+		point.StageDurationsMs[node.Name] += graphEndMs(int64(node.StartTs), int64(node.EndTs)) - int64(node.StartTs)
+	}
+	return point
+}
+
+func currentTrendPoint(pipeline models.Pipeline) models.TrendPoint {
+	point := models.TrendPoint{
+		ExecutionId:      pipeline.ExecutionId,
+		Status:           pipeline.Status,
+		StartMs:          pipeline.StartMs,
+		DurationMs:       pipeline.EndMs - pipeline.StartMs,
+		StageDurationsMs: map[string]int64{},
+	}
+	for _, stage := range pipeline.Stages {
+		if stage.StartMs == 0 {
+			continue
+		}
+		point.StageDurationsMs[stage.Name] += stage.EndMs - stage.StartMs
+	}
+	return point
+}
+
+// flakySteps formats flaky steps as "Stage/Step" for output variables.
+func flakySteps(steps []models.FlakyStep) []string {
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Stage+"/"+step.Step)
+	}
+	return names
+}
+
+// buildExecutionLink returns the Harness UI URL of an execution.
+func buildExecutionLink(config Config, executionID string) string {
+	return "https://app.harness.io/ng/account/" + config.AccID + "/ci/orgs/" + config.OrgID + "/projects/" + config.ProjectID + "/pipelines/" + config.PipelineID + "/deployments/" + executionID + "/pipeline"
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	htmlgenerator "pipeline-html-generator/internal/generators"
+	"slices"
+	"strings"
+)
+
+// Encodings of the HTML_REPORT output variable. Every encoding but raw starts
+// from the minified document.
+const (
+	encodingRaw        = "raw"
+	encodingMinified   = "minified"
+	encodingBase64     = "base64"
+	encodingGzipBase64 = "gzip+base64"
+)
+
+var htmlReportEncodings = []string{encodingRaw, encodingMinified, encodingBase64, encodingGzipBase64}
+
+// checkHTMLReportEncoding rejects an unknown html_report_encoding.
+func checkHTMLReportEncoding(encoding string) error {
+	if !slices.Contains(htmlReportEncodings, encoding) {
+		return fmt.Errorf("invalid html_report_encoding %q, expected one of %s", encoding, strings.Join(htmlReportEncodings, ", "))
+	}
+	return nil
+}
+
+// htmlReport returns the value of HTML_REPORT and what it holds: the
+// dashboard, or the compact summary when the dashboard exceeds maxSize bytes
+// once encoded. When the summary is too big as well the value is left empty
+// and the content is "none". A maxSize of 0 disables the ceiling.
+func htmlReport(outputs *outputSet, encoding string, maxSize int) (string, string, error) {
+	for _, content := range []struct{ name, format string }{{"dashboard", "html"}, {"summary", "summary"}} {
+		document, err := outputs.render(content.format)
+		if err != nil {
+			return "", "", err
+		}
+		value, err := encodeHTMLReport(document, encoding)
+		if err != nil {
+			return "", "", err
+		}
+		if maxSize <= 0 || len(value) <= maxSize {
+			return value, content.name, nil
+		}
+		fmt.Printf("| \033[33m[WARNING] - HTML_REPORT %s is %d bytes encoded as %s, over the %d bytes limit\033[0m\n", content.name, len(value), encoding, maxSize)
+	}
+	return "", "none", nil
+}
+
+func encodeHTMLReport(document []byte, encoding string) (string, error) {
+	if encoding == encodingRaw {
+		return string(document), nil
+	}
+	minified := htmlgenerator.MinifyHTML(document)
+	switch encoding {
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString(minified), nil
+	case encodingGzipBase64:
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(minified); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+	}
+	return string(minified), nil
+}
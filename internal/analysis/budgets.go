@@ -0,0 +1,137 @@
+// analysis/budgets.go
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"pipeline-html-generator/internal/models"
+	"time"
+)
+
+// Budget caps the duration of the pipelines, stages or steps matching its
+// patterns. A budget without a stage applies to the pipeline, a budget with a
+// stage but no step to the stage, and a budget with a step to the steps.
+// Patterns use path.Match syntax, e.g. "Build*" or "*".
+type Budget struct {
+	Pipeline string `json:"pipeline"`
+	Stage    string `json:"stage"`
+	Step     string `json:"step"`
+	Max      string `json:"max"` // Go duration, e.g. "5m" or "1h30m"
+	// ExcludeWait compares the running time only, leaving out approvals and other waits.
+	ExcludeWait bool `json:"excludeWait"`
+
+	max time.Duration
+}
+
+// BudgetFile is the JSON document passed with --budgets_file.
+type BudgetFile struct {
+	Budgets []Budget `json:"budgets"`
+}
+
+// LoadBudgets reads and validates a budgets file.
+func LoadBudgets(budgetsFile string) ([]Budget, error) {
+	content, err := os.ReadFile(budgetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading budgets file %s: %w", budgetsFile, err)
+	}
+	var file BudgetFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("error parsing budgets file %s: %w", budgetsFile, err)
+	}
+
+	for i := range file.Budgets {
+		budget := &file.Budgets[i]
+		if budget.Step != "" && budget.Stage == "" {
+			budget.Stage = "*"
+		}
+		for _, pattern := range []string{budget.Pipeline, budget.Stage, budget.Step} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("budgets file %s: invalid pattern %q", budgetsFile, pattern)
+			}
+		}
+		budget.max, err = time.ParseDuration(budget.Max)
+		if err != nil || budget.max <= 0 {
+			return nil, fmt.Errorf("budgets file %s: invalid max duration %q for %s", budgetsFile, budget.Max, budget.target())
+		}
+	}
+	return file.Budgets, nil
+}
+
+// CheckBudgets returns the pipeline, stages and steps exceeding their budget.
+// When several budgets match the same stage or step, the first one in the file applies.
+func CheckBudgets(pipeline models.Pipeline, budgets []Budget) []models.BudgetBreach {
+	var breaches []models.BudgetBreach
+	check := func(stage string, step string, startMs int64, endMs int64, waitMs int64) {
+		for _, budget := range budgets {
+			if !budget.matches(pipeline.Name, stage, step) {
+				continue
+			}
+			actual := elapsed(startMs, endMs)
+			if budget.ExcludeWait {
+				actual -= waitMs
+			}
+			limit := budget.max.Milliseconds()
+			if actual > limit {
+				breaches = append(breaches, models.BudgetBreach{
+					Stage:       stage,
+					Step:        step,
+					Rule:        budget.target() + " <= " + budget.max.String(),
+					BudgetMs:    limit,
+					ActualMs:    actual,
+					OverMs:      actual - limit,
+					OverPercent: float64(actual-limit) * 100 / float64(limit),
+					ExcludeWait: budget.ExcludeWait,
+				})
+			}
+			return
+		}
+	}
+
+	check("", "", pipeline.StartMs, pipeline.EndMs, pipeline.WaitMs)
+	for _, stage := range pipeline.Stages {
+		check(stage.Name, "", stage.StartMs, stage.EndMs, stage.WaitMs)
+		for _, step := range stage.Steps {
+			if step.Status == "Skipped" {
+				continue
+			}
+			check(stage.Name, step.Name, step.StartMs, step.EndMs, step.WaitMs)
+		}
+	}
+	return breaches
+}
+
+// matches reports whether the budget applies to the node, which is the
+// pipeline when stage is empty and a stage when step is empty.
+func (b Budget) matches(pipeline string, stage string, step string) bool {
+	if (stage == "") != (b.Stage == "") || (step == "") != (b.Step == "") {
+		return false
+	}
+	return match(b.Pipeline, pipeline) && match(b.Stage, stage) && match(b.Step, step)
+}
+
+// target describes what the budget applies to, e.g. "Build/*/unit".
+func (b Budget) target() string {
+	pipeline := b.Pipeline
+	if pipeline == "" {
+		pipeline = "*"
+	}
+	target := pipeline
+	if b.Stage != "" {
+		target += "/" + b.Stage
+	}
+	if b.Step != "" {
+		target += "/" + b.Step
+	}
+	return target
+}
+
+// match reports whether name matches pattern; an empty pattern matches anything.
+func match(pattern string, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadBudgets parses a budgets file with the given content.
+func loadBudgets(t *testing.T, content string) ([]Budget, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "budgets.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadBudgets(path)
+}
+
+func breach(stage string, step string, rule string, budgetMs int64, actualMs int64, excludeWait bool) models.BudgetBreach {
+	return models.BudgetBreach{
+		Stage: stage, Step: step, Rule: rule,
+		BudgetMs: budgetMs, ActualMs: actualMs, OverMs: actualMs - budgetMs,
+		OverPercent: float64(actualMs-budgetMs) * 100 / float64(budgetMs),
+		ExcludeWait: excludeWait,
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	build := stage("s1", "Build", 100*second, 300*second,
+		step("unit", "unit", "Success", 100*second, 220*second),
+		step("lint", "lint", "Success", 220*second, 230*second),
+		step("docs", "docs", "Skipped", 100*second, 1000*second),
+	)
+	approve := step("approve", "approve", "Success", 300*second, 600*second)
+	approve.WaitMs = 300 * second
+	deploy := stage("s2", "Deploy", 300*second, 700*second, approve, step("rollout", "rollout", "Success", 600*second, 700*second))
+	deploy.WaitMs = 300 * second
+	pipeline := models.Pipeline{Name: "release", StartMs: 100 * second, EndMs: 700 * second, WaitMs: 300 * second, Stages: []models.Stage{build, deploy}}
+
+	tests := []struct {
+		name    string
+		budgets string
+		want    []models.BudgetBreach
+	}{
+		{
+			name:    "the first matching budget applies",
+			budgets: `{"budgets": [{"stage": "Build", "max": "5m"}, {"stage": "*", "max": "1m"}]}`,
+			want:    []models.BudgetBreach{breach("Deploy", "", "*/* <= 1m0s", 60*second, 400*second, false)},
+		},
+		{
+			name:    "a later budget does not apply once an earlier one matched",
+			budgets: `{"budgets": [{"stage": "*", "max": "1h"}, {"stage": "Deploy", "max": "1m"}]}`,
+		},
+		{
+			name:    "waits count by default",
+			budgets: `{"budgets": [{"max": "8m"}, {"stage": "Deploy", "max": "3m"}]}`,
+			want: []models.BudgetBreach{
+				breach("", "", "* <= 8m0s", 480*second, 600*second, false),
+				breach("Deploy", "", "*/Deploy <= 3m0s", 180*second, 400*second, false),
+			},
+		},
+		{
+			name: "excludeWait compares the running time only",
+			budgets: `{"budgets": [{"max": "4m", "excludeWait": true}, {"stage": "Deploy", "max": "3m", "excludeWait": true},
+				{"step": "approve", "max": "1s", "excludeWait": true}, {"step": "rollout", "max": "1m", "excludeWait": true}]}`,
+			want: []models.BudgetBreach{
+				breach("", "", "* <= 4m0s", 240*second, 300*second, true),
+				breach("Deploy", "rollout", "*/*/rollout <= 1m0s", 60*second, 100*second, true),
+			},
+		},
+		{
+			name:    "step budgets apply to every stage by default and skip skipped steps",
+			budgets: `{"budgets": [{"step": "*", "max": "1m"}]}`,
+			want: []models.BudgetBreach{
+				breach("Build", "unit", "*/*/* <= 1m0s", 60*second, 120*second, false),
+				breach("Deploy", "approve", "*/*/* <= 1m0s", 60*second, 300*second, false),
+				breach("Deploy", "rollout", "*/*/* <= 1m0s", 60*second, 100*second, false),
+			},
+		},
+		{
+			name:    "the pipeline pattern",
+			budgets: `{"budgets": [{"pipeline": "nightly*", "stage": "*", "max": "1s"}, {"pipeline": "rel*", "stage": "B*", "max": "1m"}]}`,
+			want:    []models.BudgetBreach{breach("Build", "", "rel*/B* <= 1m0s", 60*second, 200*second, false)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			budgets, err := loadBudgets(t, test.budgets)
+			if err != nil {
+				t.Fatalf("LoadBudgets: %v", err)
+			}
+			if got := CheckBudgets(pipeline, budgets); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("CheckBudgets()\n got: %+v\nwant: %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadBudgetsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		budgets string
+		want    string
+	}{
+		{name: "not JSON", budgets: `budgets: []`, want: "error parsing budgets file"},
+		{name: "invalid pattern", budgets: `{"budgets": [{"stage": "[Build", "max": "1m"}]}`, want: `invalid pattern "[Build"`},
+		{name: "invalid duration", budgets: `{"budgets": [{"stage": "Build", "max": "5 minutes"}]}`, want: `invalid max duration "5 minutes" for */Build`},
+		{name: "zero duration", budgets: `{"budgets": [{"step": "unit", "max": "0s"}]}`, want: `invalid max duration "0s" for */*/unit`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadBudgets(t, test.budgets)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("err = %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// analysis/compare.go
+package analysis
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"slices"
+	"sort"
+)
+
+// Thresholds below which a duration change is not reported as slower or faster.
+const (
+	MinDeltaMs      = 5000
+	MinDeltaPercent = 10.0
+)
+
+// Compare returns the differences between the current execution and a previous one.
+// Stages are matched by name and steps by stage and step name.
+func Compare(current models.Pipeline, previous models.Pipeline) models.Comparison {
+	comparison := models.Comparison{
+		PreviousExecutionId:   previous.ExecutionId,
+		PreviousStatus:        previous.Status,
+		PreviousExecutionLink: previous.ExecutionLink,
+		PreviousStartMs:       previous.StartMs,
+		DurationDeltaMs:       (current.EndMs - current.StartMs) - (previous.EndMs - previous.StartMs),
+	}
+
+	currentNodes := comparableNodes(current)
+	previousNodes := comparableNodes(previous)
+
+	for _, node := range currentNodes.order {
+		now := currentNodes.byKey[node]
+		before, ok := previousNodes.byKey[node]
+		if !ok {
+			if now.Step != "" {
+				comparison.Added = append(comparison.Added, now)
+			}
+			continue
+		}
+
+		change := now
+		change.PreviousStatus = before.CurrentStatus
+		change.PreviousMs = before.CurrentMs
+		change.DeltaMs = change.CurrentMs - change.PreviousMs
+		if change.PreviousMs > 0 {
+			change.DeltaPercent = float64(change.DeltaMs) * 100 / float64(change.PreviousMs)
+		}
+
+		if change.Step != "" {
+			wasFailing := isFailed(change.PreviousStatus)
+			isFailing := isFailed(change.CurrentStatus)
+			if isFailing && !wasFailing {
+				comparison.NewlyFailing = append(comparison.NewlyFailing, change)
+			} else if wasFailing && !isFailing {
+				comparison.NewlyFixed = append(comparison.NewlyFixed, change)
+			}
+		}
+
+		if abs(change.DeltaMs) < MinDeltaMs || (change.PreviousMs > 0 && abs64(change.DeltaPercent) < MinDeltaPercent) {
+			continue
+		}
+		if change.DeltaMs > 0 {
+			comparison.Slower = append(comparison.Slower, change)
+		} else {
+			comparison.Faster = append(comparison.Faster, change)
+		}
+	}
+
+	for _, node := range previousNodes.order {
+		before := previousNodes.byKey[node]
+		if _, ok := currentNodes.byKey[node]; !ok && before.Step != "" {
+			removed := before
+			removed.PreviousStatus, removed.CurrentStatus = before.CurrentStatus, ""
+			removed.PreviousMs, removed.CurrentMs = before.CurrentMs, 0
+			comparison.Removed = append(comparison.Removed, removed)
+		}
+	}
+
+	sort.SliceStable(comparison.Slower, func(i, j int) bool { return comparison.Slower[i].DeltaMs > comparison.Slower[j].DeltaMs })
+	sort.SliceStable(comparison.Faster, func(i, j int) bool { return comparison.Faster[i].DeltaMs < comparison.Faster[j].DeltaMs })
+
+	return comparison
+}
+
+type nodeIndex struct {
+	order []string
+	byKey map[string]models.StepChange
+	steps map[string]models.Step // the step behind each step key
+}
+
+// comparableNodes indexes the stages and steps of a pipeline. Repeated stage
+// names and repeated step names in a stage (loops, matrix) are numbered so
+// they can still be matched, and the steps of a repeated stage are keyed and
+// labelled under its numbered name. Stages and steps are numbered by start
+// time, as their order in the pipeline comes from the execution graph maps
+// and changes from one parse to the next.
+func comparableNodes(pipeline models.Pipeline) nodeIndex {
+	index := nodeIndex{byKey: map[string]models.StepChange{}, steps: map[string]models.Step{}}
+	add := func(key string, change models.StepChange) (string, models.StepChange) {
+		baseKey, baseStage, baseStep := key, change.Stage, change.Step
+		for n := 2; ; n++ {
+			if _, exists := index.byKey[key]; !exists {
+				break
+			}
+			key = fmt.Sprintf("%s #%d", baseKey, n)
+			if baseStep == "" {
+				change.Stage = fmt.Sprintf("%s #%d", baseStage, n)
+			} else {
+				change.Step = fmt.Sprintf("%s #%d", baseStep, n)
+			}
+		}
+		index.order = append(index.order, key)
+		index.byKey[key] = change
+		return key, change
+	}
+
+	stages := slices.Clone(pipeline.Stages)
+	sort.SliceStable(stages, func(i, j int) bool {
+		return startedBefore(stages[i].StartMs, stages[i].ID, stages[j].StartMs, stages[j].ID)
+	})
+	for _, stage := range stages {
+		stageKey, stageChange := add(stage.Name, models.StepChange{Stage: stage.Name, CurrentStatus: stage.Status, CurrentMs: elapsed(stage.StartMs, stage.EndMs)})
+		steps := slices.Clone(stage.Steps)
+		sort.SliceStable(steps, func(i, j int) bool {
+			return startedBefore(steps[i].StartMs, steps[i].ID, steps[j].StartMs, steps[j].ID)
+		})
+		for _, step := range steps {
+			key, _ := add(stageKey+"/"+step.Name, models.StepChange{Stage: stageChange.Stage, Step: step.Name, CurrentStatus: step.Status, CurrentMs: elapsed(step.StartMs, step.EndMs)})
+			index.steps[key] = step
+		}
+	}
+	return index
+}
+
+// startedBefore orders nodes by start time, then by ID.
+func startedBefore(startMs int64, id string, otherStartMs int64, otherID string) bool {
+	if startMs != otherStartMs {
+		return startMs < otherStartMs
+	}
+	return id < otherID
+}
+
+func isFailed(status string) bool {
+	switch status {
+	case "Failed", "Errored", "Aborted", "Expired", "ApprovalRejected":
+		return true
+	}
+	return false
+}
+
+func elapsed(startMs int64, endMs int64) int64 {
+	if startMs <= 0 || endMs <= startMs {
+		return 0
+	}
+	return endMs - startMs
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func abs64(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"testing"
+)
+
+const second = int64(1000)
+
+func stage(id string, name string, startMs int64, endMs int64, steps ...models.Step) models.Stage {
+	return models.Stage{ID: id, Name: name, Status: "Success", StartMs: startMs, EndMs: endMs, Steps: steps}
+}
+
+func step(id string, name string, status string, startMs int64, endMs int64) models.Step {
+	return models.Step{ID: id, Name: name, Status: status, StartMs: startMs, EndMs: endMs}
+}
+
+// deployStage is a "Deploy" stage starting at startMs with a smoke test lasting smokeMs.
+func deployStage(id string, startMs int64, smokeStatus string, smokeMs int64) models.Stage {
+	return stage(id, "Deploy", startMs, startMs+60*second,
+		step(id+"-apply", "apply", "Success", startMs, startMs+30*second),
+		step(id+"-smoke", "smoke", smokeStatus, startMs+30*second, startMs+30*second+smokeMs),
+	)
+}
+
+// labels returns "Stage" or "Stage / Step" for every change.
+func labels(changes []models.StepChange) []string {
+	var labels []string
+	for _, change := range changes {
+		label := change.Stage
+		if change.Step != "" {
+			label += " / " + change.Step
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name           string
+		current        []models.Stage
+		previous       []models.Stage
+		newlyFailing   []string
+		newlyFixed     []string
+		slower, faster []string
+		added, removed []string
+	}{
+		{
+			name:         "repeated stages are matched in start order",
+			current:      []models.Stage{deployStage("c2", 200*second, "Failed", 10*second), deployStage("c1", 100*second, "Success", 10*second)},
+			previous:     []models.Stage{deployStage("p1", 100*second, "Success", 10*second), deployStage("p2", 200*second, "Success", 10*second)},
+			newlyFailing: []string{"Deploy #2 / smoke"},
+		},
+		{
+			name:       "steps of a repeated stage are labelled with its number",
+			current:    []models.Stage{deployStage("c1", 100*second, "Success", 10*second), deployStage("c2", 200*second, "Success", 60*second)},
+			previous:   []models.Stage{deployStage("p1", 100*second, "Success", 40*second), deployStage("p2", 200*second, "Failed", 10*second)},
+			newlyFixed: []string{"Deploy #2 / smoke"},
+			slower:     []string{"Deploy #2 / smoke"},
+			faster:     []string{"Deploy / smoke"},
+		},
+		{
+			name:     "an extra repetition adds its steps",
+			current:  []models.Stage{deployStage("c1", 100*second, "Success", 10*second), deployStage("c2", 200*second, "Success", 10*second), deployStage("c3", 300*second, "Success", 10*second)},
+			previous: []models.Stage{deployStage("p1", 100*second, "Success", 10*second), deployStage("p2", 200*second, "Success", 10*second)},
+			added:    []string{"Deploy #3 / apply", "Deploy #3 / smoke"},
+		},
+		{
+			name:     "a missing repetition removes its steps",
+			current:  []models.Stage{deployStage("c1", 100*second, "Success", 10*second)},
+			previous: []models.Stage{deployStage("p2", 200*second, "Success", 10*second), deployStage("p1", 100*second, "Success", 10*second)},
+			removed:  []string{"Deploy #2 / apply", "Deploy #2 / smoke"},
+		},
+		{
+			name: "repeated steps in a stage are numbered",
+			current: []models.Stage{stage("c", "Build", 0, 100*second,
+				step("c-2", "retry", "Success", 50*second, 60*second),
+				step("c-1", "retry", "Failed", 10*second, 20*second),
+			)},
+			previous: []models.Stage{stage("p", "Build", 0, 100*second,
+				step("p-1", "retry", "Failed", 10*second, 20*second),
+				step("p-2", "retry", "Failed", 50*second, 60*second),
+			)},
+			newlyFixed: []string{"Build / retry #2"},
+		},
+		{
+			name: "changes under the thresholds are not reported",
+			current: []models.Stage{stage("c", "Build", 0, 200*second,
+				step("c-1", "compile", "Success", 0, 4*second),
+				step("c-2", "test", "Success", 10*second, 200*second),
+			)},
+			previous: []models.Stage{stage("p", "Build", 0, 190*second,
+				step("p-1", "compile", "Success", 0, 1*second),
+				step("p-2", "test", "Success", 10*second, 190*second),
+			)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := models.Pipeline{Stages: test.current}
+			previous := models.Pipeline{ExecutionId: "previous", Stages: test.previous}
+			comparison := Compare(current, previous)
+
+			for _, list := range []struct {
+				name string
+				got  []models.StepChange
+				want []string
+			}{
+				{"NewlyFailing", comparison.NewlyFailing, test.newlyFailing},
+				{"NewlyFixed", comparison.NewlyFixed, test.newlyFixed},
+				{"Slower", comparison.Slower, test.slower},
+				{"Faster", comparison.Faster, test.faster},
+				{"Added", comparison.Added, test.added},
+				{"Removed", comparison.Removed, test.removed},
+			} {
+				if got := labels(list.got); !reflect.DeepEqual(got, list.want) {
+					t.Errorf("%s = %q, want %q", list.name, got, list.want)
+				}
+			}
+		})
+	}
+}
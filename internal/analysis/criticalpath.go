@@ -0,0 +1,95 @@
+// analysis/criticalpath.go
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+)
+
+// CriticalPath returns the nodes of graph whose durations determined its total
+// duration, in execution order. Sequential nodes (Next) are always on the path;
+// for parallel nodes (Children) only the branch that finished last is followed.
+// Nodes with children are containers and are replaced by their critical branch.
+// Hidden nodes are followed but left out of the path.
+func CriticalPath(graph models.ExecutionGraph) []models.GraphNode {
+	root := graph.RootID
+	if _, ok := graph.Nodes[root]; !ok {
+		root = findRoot(graph)
+	}
+	if root == "" {
+		return nil
+	}
+	return chainPath(graph, root, map[string]bool{})
+}
+
+// chainPath follows id and its Next edges, expanding containers into their critical branch.
+func chainPath(graph models.ExecutionGraph, id string, visited map[string]bool) []models.GraphNode {
+	var path []models.GraphNode
+	for id != "" && !visited[id] {
+		node, ok := graph.Nodes[id]
+		if !ok {
+			break
+		}
+		visited[id] = true
+
+		if len(node.Children) > 0 {
+			path = append(path, criticalBranch(graph, node.Children, visited)...)
+		} else if node.StartMs > 0 && !node.Hidden {
+			path = append(path, node)
+		}
+
+		id = ""
+		if len(node.Next) > 0 {
+			id = node.Next[0]
+		}
+	}
+	return path
+}
+
+// criticalBranch returns the path of the child chain that finished last.
+func criticalBranch(graph models.ExecutionGraph, children []string, visited map[string]bool) []models.GraphNode {
+	var best []models.GraphNode
+	var bestEnd, bestDuration int64
+	for _, child := range children {
+		branch := chainPath(graph, child, visited)
+		if len(branch) == 0 {
+			continue
+		}
+		end := branch[len(branch)-1].EndMs
+		duration := PathDuration(branch)
+		if best == nil || end > bestEnd || (end == bestEnd && duration > bestDuration) {
+			best, bestEnd, bestDuration = branch, end, duration
+		}
+	}
+	return best
+}
+
+// PathDuration sums the durations of the nodes of a path in milliseconds.
+func PathDuration(path []models.GraphNode) int64 {
+	var total int64
+	for _, node := range path {
+		if node.EndMs > node.StartMs {
+			total += node.EndMs - node.StartMs
+		}
+	}
+	return total
+}
+
+// findRoot returns a node that is nobody's child or next node.
+func findRoot(graph models.ExecutionGraph) string {
+	referenced := map[string]bool{}
+	for _, node := range graph.Nodes {
+		for _, id := range node.Children {
+			referenced[id] = true
+		}
+		for _, id := range node.Next {
+			referenced[id] = true
+		}
+	}
+	root := ""
+	for id := range graph.Nodes {
+		if !referenced[id] && (root == "" || id < root) {
+			root = id
+		}
+	}
+	return root
+}
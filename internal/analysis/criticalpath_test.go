@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"testing"
+)
+
+// graphNode returns a node running from startMs to endMs followed by next.
+func graphNode(id string, startMs int64, endMs int64, next ...string) models.GraphNode {
+	return models.GraphNode{ID: id, Name: id, StartMs: startMs, EndMs: endMs, Next: next}
+}
+
+func container(id string, next string, children ...string) models.GraphNode {
+	node := models.GraphNode{ID: id, Name: id, Children: children}
+	if next != "" {
+		node.Next = []string{next}
+	}
+	return node
+}
+
+func hidden(node models.GraphNode) models.GraphNode {
+	node.Hidden = true
+	return node
+}
+
+func graph(root string, nodes ...models.GraphNode) models.ExecutionGraph {
+	g := models.ExecutionGraph{RootID: root, Nodes: map[string]models.GraphNode{}}
+	for _, node := range nodes {
+		g.Nodes[node.ID] = node
+	}
+	return g
+}
+
+func TestCriticalPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		graph        models.ExecutionGraph
+		want         []string
+		wantDuration int64
+	}{
+		{
+			name:  "empty graph",
+			graph: graph(""),
+		},
+		{
+			name:         "sequential nodes",
+			graph:        graph("a", graphNode("a", 1, 11, "b"), graphNode("b", 11, 31, "c"), graphNode("c", 31, 36)),
+			want:         []string{"a", "b", "c"},
+			wantDuration: 35,
+		},
+		{
+			name: "parallel branches follow the one finishing last",
+			graph: graph("parallel",
+				container("parallel", "deploy", "x1", "y1"),
+				graphNode("x1", 1, 20, "x2"), graphNode("x2", 20, 50),
+				graphNode("y1", 1, 45),
+				graphNode("deploy", 50, 60),
+			),
+			want:         []string{"x1", "x2", "deploy"},
+			wantDuration: 59,
+		},
+		{
+			name: "branches finishing together follow the longest one",
+			graph: graph("parallel",
+				container("parallel", "", "short", "long"),
+				graphNode("short", 30, 50),
+				graphNode("long", 10, 50),
+			),
+			want:         []string{"long"},
+			wantDuration: 40,
+		},
+		{
+			name: "nested parallel branches",
+			graph: graph("outer",
+				container("outer", "", "a", "b"),
+				graphNode("a", 1, 10, "inner"),
+				container("inner", "", "a1", "a2"),
+				graphNode("a1", 10, 30), graphNode("a2", 10, 70),
+				graphNode("b", 1, 60),
+			),
+			want:         []string{"a", "a2"},
+			wantDuration: 69,
+		},
+		{
+			name: "hidden nodes are followed but left out",
+			graph: graph("setup",
+				hidden(graphNode("setup", 1, 5, "wrapper")),
+				hidden(container("wrapper", "cleanup", "build", "scan")),
+				graphNode("build", 5, 40),
+				hidden(graphNode("scan", 5, 90)),
+				hidden(graphNode("cleanup", 90, 95, "notify")),
+				graphNode("notify", 95, 100),
+			),
+			want:         []string{"build", "notify"},
+			wantDuration: 40,
+		},
+		{
+			name:         "nodes that never started are left out",
+			graph:        graph("a", graphNode("a", 1, 10, "skipped"), graphNode("skipped", 0, 0, "b"), graphNode("b", 10, 20)),
+			want:         []string{"a", "b"},
+			wantDuration: 19,
+		},
+		{
+			name:         "unknown root falls back to the unreferenced node",
+			graph:        graph("missing", graphNode("b", 10, 20), graphNode("a", 1, 10, "b")),
+			want:         []string{"a", "b"},
+			wantDuration: 19,
+		},
+		{
+			name:         "cycles stop",
+			graph:        graph("a", graphNode("a", 1, 10, "b"), graphNode("b", 10, 20, "a")),
+			want:         []string{"a", "b"},
+			wantDuration: 19,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := CriticalPath(test.graph)
+			var got []string
+			for _, node := range path {
+				got = append(got, node.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("CriticalPath() = %v, want %v", got, test.want)
+			}
+			if duration := PathDuration(path); duration != test.wantDuration {
+				t.Errorf("PathDuration() = %d, want %d", duration, test.wantDuration)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// analysis/dora.go
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"sort"
+	"time"
+)
+
+// DORA performance levels.
+const (
+	LevelElite  = "Elite"
+	LevelHigh   = "High"
+	LevelMedium = "Medium"
+	LevelLow    = "Low"
+	// LevelNone is reported when a metric has no data in the window.
+	LevelNone = "N/A"
+)
+
+const day = 24 * time.Hour
+
+// Dora computes deployment frequency, lead time for changes, change failure rate
+// and time to restore from the deployments that started in [windowStart, windowEnd).
+//
+//   - Deployment frequency counts successful deployments per day.
+//   - Lead time is the median time from commit to the end of the successful
+//     deployment that shipped it.
+//   - Change failure rate is the share of finished deployments that failed.
+//     Aborted deployments are left out.
+//   - Time to restore is the median time from the end of the first failed
+//     deployment of an incident to the end of the next successful one.
+func Dora(deployments []models.Deployment, windowStart time.Time, windowEnd time.Time) models.DoraReport {
+	report := models.DoraReport{
+		WindowStartMs: windowStart.UnixMilli(),
+		WindowEndMs:   windowEnd.UnixMilli(),
+		WindowDays:    int((windowEnd.Sub(windowStart) + day - 1) / day),
+	}
+
+	for _, deployment := range deployments {
+		if deployment.StartMs >= report.WindowStartMs && deployment.StartMs < report.WindowEndMs && isFinished(deployment.Status) {
+			report.Deployments = append(report.Deployments, deployment)
+		}
+	}
+	sort.SliceStable(report.Deployments, func(i, j int) bool { return report.Deployments[i].StartMs < report.Deployments[j].StartMs })
+
+	var leadTimes, restoreTimes []int64
+	var incidentStartMs int64
+	for _, deployment := range report.Deployments {
+		report.DeploymentCount++
+		if !isSuccess(deployment.Status) {
+			report.FailedDeployments++
+			if incidentStartMs == 0 {
+				incidentStartMs = deploymentEnd(deployment)
+				report.Incidents++
+			}
+			continue
+		}
+
+		report.SuccessfulDeployments++
+		if incidentStartMs != 0 {
+			restoreTimes = append(restoreTimes, deploymentEnd(deployment)-incidentStartMs)
+			incidentStartMs = 0
+		}
+		for _, commit := range deployment.Commits {
+			if commit.TimestampMs > 0 && commit.TimestampMs <= deploymentEnd(deployment) {
+				leadTimes = append(leadTimes, deploymentEnd(deployment)-commit.TimestampMs)
+			}
+		}
+	}
+	if incidentStartMs != 0 {
+		report.UnresolvedIncidents++
+	}
+
+	if report.WindowDays > 0 {
+		report.DeploymentsPerDay = float64(report.SuccessfulDeployments) / float64(report.WindowDays)
+	}
+	report.DeploymentFrequency = frequencyLevel(report.DeploymentsPerDay, report.SuccessfulDeployments)
+
+	report.LeadTimeSamples = len(leadTimes)
+	report.LeadTimeMedianMs = median(leadTimes)
+	report.LeadTime = durationLevel(report.LeadTimeMedianMs, len(leadTimes), time.Hour, 7*day, 180*day)
+
+	if report.DeploymentCount > 0 {
+		report.ChangeFailureRate = float64(report.FailedDeployments) * 100 / float64(report.DeploymentCount)
+		report.ChangeFailure = rateLevel(report.ChangeFailureRate)
+	} else {
+		report.ChangeFailure = LevelNone
+	}
+
+	report.RestoreMedianMs = median(restoreTimes)
+	report.TimeToRestore = durationLevel(report.RestoreMedianMs, len(restoreTimes), time.Hour, day, 7*day)
+
+	return report
+}
+
+func isFinished(status string) bool {
+	return isSuccess(status) || (isFailed(status) && status != "Aborted")
+}
+
+func isSuccess(status string) bool {
+	return status == "Success" || status == "IgnoreFailed"
+}
+
+func deploymentEnd(deployment models.Deployment) int64 {
+	if deployment.EndMs > 0 {
+		return deployment.EndMs
+	}
+	return deployment.StartMs
+}
+
+func median(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]int64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// frequencyLevel rates deployments per day: daily or more is Elite, weekly High, monthly Medium.
+func frequencyLevel(perDay float64, deployments int) string {
+	switch {
+	case deployments == 0:
+		return LevelLow
+	case perDay >= 1:
+		return LevelElite
+	case perDay >= 1.0/7:
+		return LevelHigh
+	case perDay >= 1.0/30:
+		return LevelMedium
+	}
+	return LevelLow
+}
+
+// durationLevel rates a median duration against the Elite, High and Medium upper bounds.
+func durationLevel(ms int64, samples int, elite time.Duration, high time.Duration, medium time.Duration) string {
+	d := time.Duration(ms) * time.Millisecond
+	switch {
+	case samples == 0:
+		return LevelNone
+	case d < elite:
+		return LevelElite
+	case d < high:
+		return LevelHigh
+	case d < medium:
+		return LevelMedium
+	}
+	return LevelLow
+}
+
+// rateLevel rates a change failure rate: up to 15% is Elite, 30% High, 45% Medium.
+func rateLevel(rate float64) string {
+	switch {
+	case rate <= 15:
+		return LevelElite
+	case rate <= 30:
+		return LevelHigh
+	case rate <= 45:
+		return LevelMedium
+	}
+	return LevelLow
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var doraWindowStart = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// at returns the time of hour:minute on the given day of the window.
+func at(day int, hour int, minute int) time.Time {
+	return doraWindowStart.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func deployment(status string, start time.Time, end time.Time, commits ...time.Time) models.Deployment {
+	deployment := models.Deployment{ExecutionId: status + start.Format("0102T1504"), Status: status, StartMs: start.UnixMilli(), EndMs: end.UnixMilli()}
+	for _, commit := range commits {
+		deployment.Commits = append(deployment.Commits, models.Commit{TimestampMs: commit.UnixMilli()})
+	}
+	return deployment
+}
+
+func TestDora(t *testing.T) {
+	windowEnd := at(10, 0, 0)
+
+	var daily []models.Deployment
+	for day := 0; day < 10; day++ {
+		daily = append(daily, deployment("Success", at(day, 10, 0), at(day, 10, 20), at(day, 9, 50)))
+	}
+
+	tests := []struct {
+		name        string
+		deployments []models.Deployment
+		want        models.DoraReport
+	}{
+		{
+			name: "no deployments",
+			want: models.DoraReport{DeploymentFrequency: LevelLow, LeadTime: LevelNone, ChangeFailure: LevelNone, TimeToRestore: LevelNone},
+		},
+		{
+			name:        "daily successful deployments",
+			deployments: daily,
+			want: models.DoraReport{
+				DeploymentCount: 10, SuccessfulDeployments: 10, DeploymentsPerDay: 1, DeploymentFrequency: LevelElite,
+				LeadTimeMedianMs: (30 * time.Minute).Milliseconds(), LeadTimeSamples: 10, LeadTime: LevelElite,
+				ChangeFailure: LevelElite, TimeToRestore: LevelNone,
+			},
+		},
+		{
+			name: "deployments outside the window, aborted and unfinished ones are left out",
+			deployments: []models.Deployment{
+				deployment("Success", doraWindowStart.Add(-time.Minute), doraWindowStart.Add(time.Minute)),
+				deployment("Success", windowEnd, windowEnd.Add(time.Minute)),
+				deployment("Aborted", at(1, 9, 0), at(1, 9, 5)),
+				deployment("Running", at(3, 9, 0), time.Time{}),
+				deployment("Failed", at(1, 10, 0), at(1, 10, 30)),
+				deployment("Success", at(2, 10, 0), at(2, 10, 30)),
+			},
+			want: models.DoraReport{
+				DeploymentCount: 2, SuccessfulDeployments: 1, FailedDeployments: 1, DeploymentsPerDay: 0.1, DeploymentFrequency: LevelMedium,
+				LeadTime: LevelNone, ChangeFailureRate: 50, ChangeFailure: LevelLow,
+				RestoreMedianMs: (24 * time.Hour).Milliseconds(), Incidents: 1, TimeToRestore: LevelMedium,
+			},
+		},
+		{
+			name: "runs of failures are one incident each, restored by the next success",
+			deployments: []models.Deployment{
+				deployment("Success", at(0, 10, 0), at(0, 10, 30)),
+				deployment("Failed", at(1, 10, 0), at(1, 10, 20)),
+				deployment("Errored", at(2, 10, 0), at(2, 10, 20)),
+				deployment("Success", at(2, 12, 0), at(2, 12, 20)),
+				deployment("Failed", at(3, 10, 0), at(3, 10, 10)),
+				deployment("IgnoreFailed", at(3, 11, 0), at(3, 11, 10)),
+			},
+			want: models.DoraReport{
+				DeploymentCount: 6, SuccessfulDeployments: 3, FailedDeployments: 3, DeploymentsPerDay: 0.3, DeploymentFrequency: LevelHigh,
+				LeadTime: LevelNone, ChangeFailureRate: 50, ChangeFailure: LevelLow,
+				RestoreMedianMs: (27 * time.Hour / 2).Milliseconds(), Incidents: 2, TimeToRestore: LevelHigh,
+			},
+		},
+		{
+			name: "unresolved incident",
+			deployments: []models.Deployment{
+				deployment("Failed", at(5, 10, 0), at(5, 10, 20)),
+				deployment("Success", at(4, 10, 0), at(4, 10, 20)),
+				deployment("Success", at(3, 10, 0), at(3, 10, 20)),
+				deployment("Success", at(2, 10, 0), at(2, 10, 20)),
+			},
+			want: models.DoraReport{
+				DeploymentCount: 4, SuccessfulDeployments: 3, FailedDeployments: 1, DeploymentsPerDay: 0.3, DeploymentFrequency: LevelHigh,
+				LeadTime: LevelNone, ChangeFailureRate: 25, ChangeFailure: LevelHigh,
+				Incidents: 1, UnresolvedIncidents: 1, TimeToRestore: LevelNone,
+			},
+		},
+		{
+			name: "lead time is the median over commits shipped by successful deployments",
+			deployments: []models.Deployment{
+				deployment("Success", at(0, 10, 0), at(0, 12, 0), at(0, 10, 0), at(0, 8, 0)),
+				deployment("Failed", at(3, 10, 0), at(3, 12, 0), at(3, 9, 0)),
+				deployment("Success", at(5, 10, 0), at(5, 12, 0), at(-25, 12, 0), at(5, 13, 0), time.UnixMilli(0)),
+			},
+			want: models.DoraReport{
+				DeploymentCount: 3, SuccessfulDeployments: 2, FailedDeployments: 1, DeploymentsPerDay: 0.2, DeploymentFrequency: LevelHigh,
+				LeadTimeMedianMs: (4 * time.Hour).Milliseconds(), LeadTimeSamples: 3, LeadTime: LevelHigh,
+				ChangeFailureRate: 100.0 / 3, ChangeFailure: LevelMedium,
+				RestoreMedianMs: (48 * time.Hour).Milliseconds(), Incidents: 1, TimeToRestore: LevelMedium,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Dora(test.deployments, doraWindowStart, windowEnd)
+			if got.WindowStartMs != doraWindowStart.UnixMilli() || got.WindowEndMs != windowEnd.UnixMilli() || got.WindowDays != 10 {
+				t.Errorf("window = %d..%d, %d days", got.WindowStartMs, got.WindowEndMs, got.WindowDays)
+			}
+			for i := 1; i < len(got.Deployments); i++ {
+				if got.Deployments[i].StartMs < got.Deployments[i-1].StartMs {
+					t.Errorf("deployments are not sorted oldest first: %v", got.Deployments)
+				}
+			}
+			if len(got.Deployments) != got.DeploymentCount {
+				t.Errorf("%d deployments listed, want %d", len(got.Deployments), got.DeploymentCount)
+			}
+
+			got.WindowStartMs, got.WindowEndMs, got.WindowDays, got.Deployments = 0, 0, 0, nil
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Dora()\n got: %+v\nwant: %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// analysis/flaky.go
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"sort"
+)
+
+type stepRun struct {
+	executionID string
+	commit      string
+	failed      bool
+	status      string
+}
+
+// noSignalStatuses are step statuses that say nothing about whether the step passes.
+var noSignalStatuses = map[string]bool{
+	"Skipped":             true,
+	"Aborted":             true,
+	"Running":             true,
+	"AsyncWaiting":        true,
+	"Queued":              true,
+	"NotStarted":          true,
+	"Paused":              true,
+	"ApprovalWaiting":     true,
+	"ResourceWaiting":     true,
+	"InterventionWaiting": true,
+}
+
+// DetectFlakySteps scores the steps of executions that switched between success
+// and failure while the commit stayed the same. Executions without commit
+// information (e.g. CD pipelines) are treated as having no code change.
+// Skipped, aborted and unfinished runs carry no signal and are ignored. The score is the
+// share of consecutive runs that flipped, from 0 to 100; steps scoring below
+// minScore are left out.
+func DetectFlakySteps(executions []models.Pipeline, minScore float64) []models.FlakyStep {
+	ordered := make([]models.Pipeline, len(executions))
+	copy(ordered, executions)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].StartMs < ordered[j].StartMs })
+
+	var keys []string
+	runs := map[string][]stepRun{}
+	names := map[string]models.FlakyStep{}
+	for _, execution := range ordered {
+		index := comparableNodes(execution)
+		for _, node := range index.order {
+			change := index.byKey[node]
+			if change.Step == "" || noSignalStatuses[change.CurrentStatus] {
+				continue
+			}
+			if _, ok := runs[node]; !ok {
+				keys = append(keys, node)
+				names[node] = models.FlakyStep{Stage: change.Stage, Step: change.Step}
+			}
+			runs[node] = append(runs[node], stepRun{
+				executionID: execution.ExecutionId,
+				commit:      execution.CommitSha,
+				failed:      isFailed(change.CurrentStatus) || failedWithoutStatus(change, index.steps[node]),
+				status:      change.CurrentStatus,
+			})
+		}
+	}
+
+	var flaky []models.FlakyStep
+	for _, key := range keys {
+		history := runs[key]
+		if len(history) < 2 {
+			continue
+		}
+
+		step := names[key]
+		step.Runs = len(history)
+		step.LastStatus = history[len(history)-1].status
+		for i, run := range history {
+			if run.failed {
+				step.Failures++
+			}
+			if i == 0 {
+				continue
+			}
+			previous := history[i-1]
+			if run.failed != previous.failed && run.commit == previous.commit {
+				step.Flips++
+				step.Executions = append(step.Executions, run.executionID)
+			}
+		}
+		if step.Flips == 0 {
+			continue
+		}
+
+		step.Score = float64(step.Flips) * 100 / float64(step.Runs-1)
+		if step.Score >= minScore {
+			flaky = append(flaky, step)
+		}
+	}
+
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].Score != flaky[j].Score {
+			return flaky[i].Score > flaky[j].Score
+		}
+		return flaky[i].Flips > flaky[j].Flips
+	})
+	return flaky
+}
+
+// failedWithoutStatus reports whether a step failed but had its failure ignored,
+// which Harness reports with a failure message and no failed status.
+func failedWithoutStatus(change models.StepChange, step models.Step) bool {
+	return change.CurrentStatus != "Success" && step.FailureInfo.Message != ""
+}
@@ -0,0 +1,141 @@
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"testing"
+)
+
+// run is an execution of commit whose "Build" stage holds one step per status.
+// Steps are named after their position: "unit", "lint", then "e2e".
+func run(id string, commit string, startMs int64, statuses ...string) models.Pipeline {
+	names := []string{"unit", "lint", "e2e"}
+	build := stage(id+"-build", "Build", startMs, startMs+10*second)
+	for i, status := range statuses {
+		build.Steps = append(build.Steps, step(id+"-"+names[i], names[i], status, startMs+int64(i)*second, startMs+int64(i+1)*second))
+	}
+	return models.Pipeline{ExecutionId: id, CommitSha: commit, StartMs: startMs, Stages: []models.Stage{build}}
+}
+
+// ignoredFailure returns a step that failed with its failure ignored.
+func ignoredFailure(s models.Step) models.Step {
+	s.FailureInfo.Message = "exit status 1"
+	return s
+}
+
+// deployRun is an execution running the "Deploy" stage twice. The smoke test of
+// the second one has its failure ignored when failed is set.
+func deployRun(id string, startMs int64, failed bool) models.Pipeline {
+	first := stage(id+"-d1", "Deploy", startMs, startMs+10*second, step(id+"-s1", "smoke", "", startMs, startMs+second))
+	repeat := stage(id+"-d2", "Deploy", startMs+20*second, startMs+30*second, step(id+"-s2", "smoke", "Success", startMs+20*second, startMs+21*second))
+	if failed {
+		repeat.Steps[0] = ignoredFailure(repeat.Steps[0])
+		repeat.Steps[0].Status = "IgnoreFailed"
+	}
+	return models.Pipeline{ExecutionId: id, CommitSha: "abc", StartMs: startMs, Stages: []models.Stage{repeat, first}}
+}
+
+func TestDetectFlakySteps(t *testing.T) {
+	tests := []struct {
+		name       string
+		executions []models.Pipeline
+		minScore   float64
+		want       []models.FlakyStep
+	}{
+		{
+			name: "every run flipped on the same commit",
+			executions: []models.Pipeline{
+				run("e3", "abc", 3000*second, "Success"),
+				run("e1", "abc", 1000*second, "Success"),
+				run("e4", "abc", 4000*second, "Failed"),
+				run("e2", "abc", 2000*second, "Failed"),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "unit", Runs: 4, Failures: 2, Flips: 3, Score: 100, LastStatus: "Failed", Executions: []string{"e2", "e3", "e4"}},
+			},
+		},
+		{
+			name: "flips across a commit change are not counted",
+			executions: []models.Pipeline{
+				run("e1", "abc", 1000*second, "Success"),
+				run("e2", "def", 2000*second, "Failed"),
+				run("e3", "def", 3000*second, "Success"),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "unit", Runs: 3, Failures: 1, Flips: 1, Score: 50, LastStatus: "Success", Executions: []string{"e3"}},
+			},
+		},
+		{
+			name: "executions without commit information count as the same code",
+			executions: []models.Pipeline{
+				run("e1", "", 1000*second, "Errored"),
+				run("e2", "", 2000*second, "Success"),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "unit", Runs: 2, Failures: 1, Flips: 1, Score: 100, LastStatus: "Success", Executions: []string{"e2"}},
+			},
+		},
+		{
+			name: "skipped, aborted and unfinished runs carry no signal",
+			executions: []models.Pipeline{
+				run("e1", "abc", 1000*second, "Success"),
+				run("e2", "abc", 2000*second, "Skipped"),
+				run("e3", "abc", 3000*second, "Failed"),
+				run("e4", "abc", 4000*second, "Aborted"),
+				run("e5", "abc", 5000*second, "Running"),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "unit", Runs: 2, Failures: 1, Flips: 1, Score: 100, LastStatus: "Failed", Executions: []string{"e3"}},
+			},
+		},
+		{
+			name: "steps below the minimum score are left out",
+			executions: []models.Pipeline{
+				run("e1", "abc", 1000*second, "Success", "Success"),
+				run("e2", "abc", 2000*second, "Success", "Failed"),
+				run("e3", "abc", 3000*second, "Success", "Failed"),
+				run("e4", "abc", 4000*second, "Failed", "Success"),
+			},
+			minScore: 50,
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "lint", Runs: 4, Failures: 2, Flips: 2, Score: 200.0 / 3, LastStatus: "Success", Executions: []string{"e2", "e4"}},
+			},
+		},
+		{
+			name: "sorted by score, then by flips",
+			executions: []models.Pipeline{
+				run("e1", "abc", 1000*second, "Success", "Success", "Success"),
+				run("e2", "abc", 2000*second, "Failed", "Success", "Failed"),
+				run("e3", "abc", 3000*second, "Failed", "Failed", "Success"),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Build", Step: "e2e", Runs: 3, Failures: 1, Flips: 2, Score: 100, LastStatus: "Success", Executions: []string{"e2", "e3"}},
+				{Stage: "Build", Step: "unit", Runs: 3, Failures: 2, Flips: 1, Score: 50, LastStatus: "Failed", Executions: []string{"e2"}},
+				{Stage: "Build", Step: "lint", Runs: 3, Failures: 1, Flips: 1, Score: 50, LastStatus: "Failed", Executions: []string{"e3"}},
+			},
+		},
+		{
+			name: "ignored failures count for the repeated stage they happened in",
+			executions: []models.Pipeline{
+				deployRun("e1", 1000*second, false),
+				deployRun("e2", 2000*second, true),
+				deployRun("e3", 3000*second, false),
+			},
+			want: []models.FlakyStep{
+				{Stage: "Deploy #2", Step: "smoke", Runs: 3, Failures: 1, Flips: 2, Score: 100, LastStatus: "Success", Executions: []string{"e2", "e3"}},
+			},
+		},
+		{
+			name:       "a single run is not enough",
+			executions: []models.Pipeline{run("e1", "abc", 1000*second, "Failed")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DetectFlakySteps(test.executions, test.minScore); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("DetectFlakySteps()\n got: %+v\nwant: %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// analysis/jira.go
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// issueKeyPattern matches Jira issue keys such as PAY-123 or ABC2_X-7.
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+// IssueKeys returns the distinct Jira issue keys referenced in messages, in
+// order of appearance. When projects is not empty only keys of those projects
+// are kept, which filters out look-alikes such as UTF-8.
+func IssueKeys(messages []string, projects []string) []string {
+	allowed := map[string]bool{}
+	for _, project := range projects {
+		allowed[strings.ToUpper(strings.TrimSpace(project))] = true
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, message := range messages {
+		for _, key := range issueKeyPattern.FindAllString(message, -1) {
+			project, _, _ := strings.Cut(key, "-")
+			if seen[key] || (len(allowed) > 0 && !allowed[project]) {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
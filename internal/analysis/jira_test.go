@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIssueKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		projects []string
+		want     []string
+	}{
+		{
+			name:     "order of appearance without duplicates",
+			messages: []string{"PAY-12 fix rounding, see OPS-3", "Merge PAY-12 and PAY-7 (OPS-3)"},
+			want:     []string{"PAY-12", "OPS-3", "PAY-7"},
+		},
+		{
+			name:     "not keys",
+			messages: []string{"pay-12 lower case, X-1 single letter, PAY-0 and PAY-012 leading zero, APAY-1a, PAY_1"},
+		},
+		{
+			name:     "digits and underscores in the project",
+			messages: []string{"ABC2_X-7: refactor"},
+			want:     []string{"ABC2_X-7"},
+		},
+		{
+			name:     "project filter",
+			messages: []string{"PAY-12 and OPS-3, INFRA-9"},
+			projects: []string{" pay", "INFRA"},
+			want:     []string{"PAY-12", "INFRA-9"},
+		},
+		{
+			name:     "look-alikes are kept without a filter",
+			messages: []string{"Read the file as UTF-8 and hash it with SHA-256 for PAY-4"},
+			want:     []string{"UTF-8", "SHA-256", "PAY-4"},
+		},
+		{
+			name:     "look-alikes are dropped by the filter",
+			messages: []string{"Read the file as UTF-8 and hash it with SHA-256 for PAY-4", "ISO-8859 fallback"},
+			projects: []string{"PAY"},
+			want:     []string{"PAY-4"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IssueKeys(test.messages, test.projects); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("IssueKeys() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
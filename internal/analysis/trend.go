@@ -0,0 +1,44 @@
+// analysis/trend.go
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"sort"
+)
+
+// NewTrend orders points oldest first, lists the stages they ran and computes
+// the success rate of the finished executions.
+func NewTrend(points []models.TrendPoint) models.Trend {
+	trend := models.Trend{Executions: make([]models.TrendPoint, len(points))}
+	copy(trend.Executions, points)
+	sort.SliceStable(trend.Executions, func(i, j int) bool { return trend.Executions[i].StartMs < trend.Executions[j].StartMs })
+
+	seen := map[string]bool{}
+	finished, succeeded := 0, 0
+	for _, point := range trend.Executions {
+		var stages []string
+		for stage := range point.StageDurationsMs {
+			if !seen[stage] {
+				stages = append(stages, stage)
+			}
+		}
+		sort.Strings(stages)
+		for _, stage := range stages {
+			seen[stage] = true
+			trend.Stages = append(trend.Stages, stage)
+		}
+
+		switch {
+		case point.Status == "Success" || point.Status == "IgnoreFailed":
+			finished++
+			succeeded++
+		case isFailed(point.Status):
+			finished++
+		}
+	}
+
+	if finished > 0 {
+		trend.SuccessRate = float64(succeeded) * 100 / float64(finished)
+	}
+	return trend
+}
@@ -0,0 +1,167 @@
+// analysis/wait.go
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"sort"
+	"time"
+)
+
+// Reasons a step spends time waiting instead of running.
+const (
+	WaitApproval     = "Approval"
+	WaitResource     = "Resource"
+	WaitBarrier      = "Barrier"
+	WaitQueue        = "Queue"
+	WaitTimer        = "Wait"
+	WaitPaused       = "Paused"
+	WaitIntervention = "Intervention"
+	WaitAsync        = "AsyncWaiting"
+)
+
+// waitingStepTypes are steps whose whole duration is waiting.
+var waitingStepTypes = map[string]string{
+	"HarnessApproval":    WaitApproval,
+	"JiraApproval":       WaitApproval,
+	"ServiceNowApproval": WaitApproval,
+	"CustomApproval":     WaitApproval,
+	"ResourceConstraint": WaitResource,
+	"Barrier":            WaitBarrier,
+	"Queue":              WaitQueue,
+	"Wait":               WaitTimer,
+}
+
+// waitingStatuses are the statuses of a step that is currently waiting.
+var waitingStatuses = map[string]string{
+	"ApprovalWaiting":           WaitApproval,
+	"ResourceWaiting":           WaitResource,
+	"InterventionWaiting":       WaitIntervention,
+	"WaitStepRunning":           WaitTimer,
+	"QueuedLicenseLimitReached": WaitQueue,
+	"AsyncWaiting":              WaitAsync,
+}
+
+// SplitWaitTime separates waiting from running time. It fills in the wait
+// intervals, WaitMs and RunMs of every step and stage, and WaitMs, RunMs and
+// WaitByReason of the pipeline. Parallel waits are only counted once.
+func SplitWaitTime(pipeline *models.Pipeline) {
+	var all []models.WaitInterval
+	for i := range pipeline.Stages {
+		stage := &pipeline.Stages[i]
+		var stageWaits []models.WaitInterval
+		for j := range stage.Steps {
+			step := &stage.Steps[j]
+			step.Waits = stepWaits(*step)
+			step.WaitMs = unionMs(step.Waits)
+			step.RunMs = elapsed(step.StartMs, step.EndMs) - step.WaitMs
+			if step.RunMs < 0 {
+				step.RunMs = 0
+			}
+			stageWaits = append(stageWaits, step.Waits...)
+		}
+		stage.WaitMs = unionMs(clamp(stageWaits, stage.StartMs, stage.EndMs))
+		stage.RunMs = elapsed(stage.StartMs, stage.EndMs) - stage.WaitMs
+		if stage.RunMs < 0 {
+			stage.RunMs = 0
+		}
+		all = append(all, stageWaits...)
+	}
+
+	pipeline.WaitMs = unionMs(all)
+	pipeline.RunMs = elapsed(pipeline.StartMs, pipeline.EndMs) - pipeline.WaitMs
+	if pipeline.RunMs < 0 {
+		pipeline.RunMs = 0
+	}
+	pipeline.WaitByReason = nil
+	byReason := map[string][]models.WaitInterval{}
+	for _, wait := range all {
+		byReason[wait.Reason] = append(byReason[wait.Reason], wait)
+	}
+	for reason, waits := range byReason {
+		if pipeline.WaitByReason == nil {
+			pipeline.WaitByReason = map[string]int64{}
+		}
+		pipeline.WaitByReason[reason] = unionMs(waits)
+	}
+}
+
+// stepWaits returns the periods a step waited: its whole span for approval,
+// barrier, queue and similar steps, the time since it started for a step that
+// is waiting right now, and the pauses and manual interventions of its
+// interrupt history.
+func stepWaits(step models.Step) []models.WaitInterval {
+	endMs := step.EndMs
+	if endMs <= 0 {
+		endMs = time.Now().UnixMilli()
+	}
+	if step.StartMs <= 0 || endMs <= step.StartMs {
+		return nil
+	}
+
+	if reason, ok := waitingStepTypes[step.StepType]; ok {
+		return []models.WaitInterval{{Reason: reason, StartMs: step.StartMs, EndMs: endMs}}
+	}
+	if reason, ok := waitingStatuses[step.Status]; ok {
+		return []models.WaitInterval{{Reason: reason, StartMs: step.StartMs, EndMs: endMs}}
+	}
+
+	interrupts := make([]models.Interrupt, len(step.Interrupts))
+	copy(interrupts, step.Interrupts)
+	sort.SliceStable(interrupts, func(i, j int) bool { return interrupts[i].TookEffectAt < interrupts[j].TookEffectAt })
+
+	var waits []models.WaitInterval
+	for i, interrupt := range interrupts {
+		var reason string
+		switch interrupt.InterruptType {
+		case "PAUSE", "PAUSE_ALL":
+			reason = WaitPaused
+		case "WAITING_FOR_MANUAL_INTERVENTION":
+			reason = WaitIntervention
+		default:
+			continue
+		}
+		waitEnd := endMs
+		if i+1 < len(interrupts) {
+			waitEnd = interrupts[i+1].TookEffectAt
+		}
+		waits = append(waits, models.WaitInterval{Reason: reason, StartMs: interrupt.TookEffectAt, EndMs: waitEnd})
+	}
+	return clamp(waits, step.StartMs, endMs)
+}
+
+// clamp trims intervals to [startMs, endMs] and drops the empty ones.
+func clamp(waits []models.WaitInterval, startMs int64, endMs int64) []models.WaitInterval {
+	var clamped []models.WaitInterval
+	for _, wait := range waits {
+		if startMs > 0 && wait.StartMs < startMs {
+			wait.StartMs = startMs
+		}
+		if endMs > 0 && wait.EndMs > endMs {
+			wait.EndMs = endMs
+		}
+		if wait.EndMs > wait.StartMs {
+			clamped = append(clamped, wait)
+		}
+	}
+	return clamped
+}
+
+// unionMs returns the time covered by intervals, counting overlaps once.
+func unionMs(waits []models.WaitInterval) int64 {
+	sorted := make([]models.WaitInterval, len(waits))
+	copy(sorted, waits)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartMs < sorted[j].StartMs })
+
+	var total, coveredUntil int64
+	for _, wait := range sorted {
+		start := wait.StartMs
+		if start < coveredUntil {
+			start = coveredUntil
+		}
+		if wait.EndMs > start {
+			total += wait.EndMs - start
+			coveredUntil = wait.EndMs
+		}
+	}
+	return total
+}
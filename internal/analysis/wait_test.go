@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"pipeline-html-generator/internal/models"
+	"reflect"
+	"testing"
+)
+
+func TestUnionMs(t *testing.T) {
+	tests := []struct {
+		name  string
+		waits [][2]int64
+		want  int64
+	}{
+		{name: "no waits", want: 0},
+		{name: "disjoint", waits: [][2]int64{{10, 20}, {30, 35}}, want: 15},
+		{name: "overlapping", waits: [][2]int64{{10, 30}, {20, 40}}, want: 30},
+		{name: "nested", waits: [][2]int64{{10, 50}, {20, 30}, {25, 45}}, want: 40},
+		{name: "touching", waits: [][2]int64{{10, 20}, {20, 30}}, want: 20},
+		{name: "unsorted", waits: [][2]int64{{60, 70}, {10, 30}, {25, 40}}, want: 40},
+		{name: "empty and reversed intervals", waits: [][2]int64{{10, 10}, {30, 20}, {40, 45}}, want: 5},
+		{name: "overlap after a gap", waits: [][2]int64{{0, 10}, {50, 80}, {60, 70}, {75, 90}}, want: 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var waits []models.WaitInterval
+			for _, wait := range test.waits {
+				waits = append(waits, models.WaitInterval{Reason: WaitApproval, StartMs: wait[0], EndMs: wait[1]})
+			}
+			if got := unionMs(waits); got != test.want {
+				t.Errorf("unionMs(%v) = %d, want %d", test.waits, got, test.want)
+			}
+		})
+	}
+}
+
+func interrupt(interruptType string, tookEffectAt int64) models.Interrupt {
+	return models.Interrupt{InterruptType: interruptType, TookEffectAt: tookEffectAt}
+}
+
+func TestSplitWaitTime(t *testing.T) {
+	at := func(seconds int64) int64 { return 1_000_000*second + seconds*second }
+
+	approval := step("a1", "approve", "Success", at(10), at(30))
+	approval.StepType = "HarnessApproval"
+	jiraApproval := step("a2", "jira", "Success", at(20), at(40))
+	jiraApproval.StepType = "JiraApproval"
+	build := step("b", "build", "Success", at(40), at(60))
+	build.Interrupts = []models.Interrupt{interrupt("RESUME", at(50)), interrupt("PAUSE", at(45))}
+	deploy := step("d", "deploy", "Success", at(60), at(100))
+	deploy.Interrupts = []models.Interrupt{interrupt("PAUSE", at(70)), interrupt("RESUME_ALL", at(75)), interrupt("WAITING_FOR_MANUAL_INTERVENTION", at(90))}
+	pausedBefore := step("e", "early", "Success", at(20), at(30))
+	pausedBefore.Interrupts = []models.Interrupt{interrupt("PAUSE_ALL", at(5)), interrupt("RESUME_ALL", at(25))}
+
+	pipeline := models.Pipeline{
+		StartMs: at(0),
+		EndMs:   at(100),
+		Stages: []models.Stage{
+			stage("s1", "Approve and build", at(0), at(60), approval, jiraApproval, build),
+			stage("s2", "Deploy", at(60), at(100), deploy),
+			stage("s3", "Parallel", at(20), at(30), pausedBefore),
+		},
+	}
+	SplitWaitTime(&pipeline)
+
+	tests := []struct {
+		name   string
+		waitMs int64
+		runMs  int64
+	}{
+		{name: "approve", waitMs: 20 * second, runMs: 0},
+		{name: "jira", waitMs: 20 * second, runMs: 0},
+		{name: "build", waitMs: 5 * second, runMs: 15 * second},
+		{name: "deploy", waitMs: 15 * second, runMs: 25 * second},
+		{name: "early", waitMs: 5 * second, runMs: 5 * second},
+		{name: "Approve and build", waitMs: 35 * second, runMs: 25 * second},
+		{name: "Deploy", waitMs: 15 * second, runMs: 25 * second},
+		{name: "Parallel", waitMs: 5 * second, runMs: 5 * second},
+		{name: "pipeline", waitMs: 50 * second, runMs: 50 * second},
+	}
+
+	measured := map[string][2]int64{"pipeline": {pipeline.WaitMs, pipeline.RunMs}}
+	for _, stage := range pipeline.Stages {
+		measured[stage.Name] = [2]int64{stage.WaitMs, stage.RunMs}
+		for _, step := range stage.Steps {
+			measured[step.Name] = [2]int64{step.WaitMs, step.RunMs}
+		}
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := measured[test.name]; got != [2]int64{test.waitMs, test.runMs} {
+				t.Errorf("wait, run = %d, %d ms, want %d, %d ms", got[0], got[1], test.waitMs, test.runMs)
+			}
+		})
+	}
+
+	wantByReason := map[string]int64{WaitApproval: 30 * second, WaitPaused: 15 * second, WaitIntervention: 10 * second}
+	if !reflect.DeepEqual(pipeline.WaitByReason, wantByReason) {
+		t.Errorf("WaitByReason = %v, want %v", pipeline.WaitByReason, wantByReason)
+	}
+}
@@ -0,0 +1,94 @@
+// generators/badge.go
+package htmlgenerator
+
+import (
+	"fmt"
+	"html"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// BadgeOptions configures the status badge. Label and Text accept the
+// {pipeline}, {status}, {duration} and {executionId} placeholders.
+type BadgeOptions struct {
+	Label        string
+	Text         string
+	ShowDuration bool
+}
+
+const badgeLabelColor = "#555555"
+
+// GenerateBadgeSVG renders a shields style SVG badge with the pipeline name and status.
+// The status side uses the same color as the status class in the dashboard.
+func GenerateBadgeSVG(pipeline models.Pipeline, opts Options) (string, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating status badge...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	badge := opts.Badge
+	if badge.Label == "" {
+		badge.Label = "{pipeline}"
+	}
+	if badge.Text == "" {
+		badge.Text = "{status}"
+	}
+
+	duration := formatDuration(pipeline.Duration)
+	replacer := strings.NewReplacer(
+		"{pipeline}", pipeline.Name,
+		"{status}", pipeline.Status,
+		"{duration}", duration,
+		"{executionId}", pipeline.ExecutionId,
+	)
+	label := replacer.Replace(badge.Label)
+	text := replacer.Replace(badge.Text)
+	if badge.ShowDuration && !strings.Contains(badge.Text, "{duration}") {
+		text += " | " + duration
+	}
+
+	// the dashboard tints with translucent status colors, a badge needs them opaque to stay readable
+	color := opaqueColor(opts.ReportTheme().StatusColor(pipeline.Status), "#9f9f9f")
+
+	labelWidth := badgeTextWidth(label) + 10
+	textWidth := badgeTextWidth(text) + 10
+	width := labelWidth + textWidth
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, html.EscapeString(label), html.EscapeString(text))
+	fmt.Fprintf(&svg, `<title>%s: %s</title>`, html.EscapeString(label), html.EscapeString(text))
+	svg.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&svg, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	svg.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&svg, `<rect width="%d" height="20" fill="%s"/>`, labelWidth, badgeLabelColor)
+	fmt.Fprintf(&svg, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, textWidth, html.EscapeString(color))
+	fmt.Fprintf(&svg, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	svg.WriteString(`</g>`)
+	svg.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&svg, `<text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text>`, float64(labelWidth)/2, html.EscapeString(label))
+	fmt.Fprintf(&svg, `<text x="%.1f" y="14">%s</text>`, float64(labelWidth)/2, html.EscapeString(label))
+	fmt.Fprintf(&svg, `<text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text>`, float64(labelWidth)+float64(textWidth)/2, html.EscapeString(text))
+	fmt.Fprintf(&svg, `<text x="%.1f" y="14">%s</text>`, float64(labelWidth)+float64(textWidth)/2, html.EscapeString(text))
+	svg.WriteString(`</g></svg>`)
+
+	return svg.String(), nil
+}
+
+// badgeTextWidth approximates the rendered width of text in 11px Verdana.
+func badgeTextWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("ijlI.,:;|!'", r):
+			width += 3.5
+		case strings.ContainsRune("frt() -", r):
+			width += 5
+		case strings.ContainsRune("mwMW", r):
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 6.7
+		}
+	}
+	return int(width + 0.5)
+}
@@ -0,0 +1,18 @@
+// generators/budgets.go
+package htmlgenerator
+
+import "pipeline-html-generator/internal/models"
+
+// BudgetBreaches lists the pipeline, stages and steps that ran longer than their budget.
+type BudgetBreaches []models.BudgetBreach
+
+// Find returns the breach of a stage (step empty) or step, or nil when it stayed within budget.
+// Find "" "" returns the pipeline breach.
+func (b BudgetBreaches) Find(stage string, step string) *models.BudgetBreach {
+	for i := range b {
+		if b[i].Stage == stage && b[i].Step == step {
+			return &b[i]
+		}
+	}
+	return nil
+}
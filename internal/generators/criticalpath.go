@@ -0,0 +1,94 @@
+// generators/criticalpath.go
+package htmlgenerator
+
+import (
+	"pipeline-html-generator/internal/analysis"
+	"pipeline-html-generator/internal/models"
+)
+
+// CriticalPath is the chain of stages and steps that determined the total pipeline duration.
+type CriticalPath struct {
+	Nodes      []CriticalNode
+	DurationMs int64
+	Duration   string  // formatted with formatDuration
+	Share      float64 // percentage of the total pipeline duration
+}
+
+// CriticalNode is one stage or step on the critical path. Step is empty when
+// the stage has no step graph.
+type CriticalNode struct {
+	Stage      string
+	Step       string
+	DurationMs int64
+	Duration   string
+	Share      float64
+}
+
+// ComputeCriticalPath follows the stage graph and, inside every stage on the
+// path, the step graph to find the nodes where optimization pays off.
+func ComputeCriticalPath(pipeline models.Pipeline) CriticalPath {
+	var path CriticalPath
+	stages := map[string]models.Stage{}
+	for _, stage := range pipeline.Stages {
+		stages[stage.ID] = stage
+	}
+
+	for _, stageNode := range analysis.CriticalPath(pipeline.Graph) {
+		stage, ok := stages[stageNode.ID]
+		if !ok {
+			continue
+		}
+		steps := analysis.CriticalPath(stage.Graph)
+		if len(steps) == 0 {
+			path.Nodes = append(path.Nodes, CriticalNode{Stage: stage.Name, DurationMs: nodeDuration(stageNode)})
+			continue
+		}
+		for _, stepNode := range steps {
+			path.Nodes = append(path.Nodes, CriticalNode{Stage: stage.Name, Step: stepNode.Name, DurationMs: nodeDuration(stepNode)})
+		}
+	}
+
+	for _, node := range path.Nodes {
+		path.DurationMs += node.DurationMs
+	}
+	total := pipeline.EndMs - pipeline.StartMs
+	if total <= 0 {
+		total = path.DurationMs
+	}
+	for i := range path.Nodes {
+		path.Nodes[i].Duration = formatDuration(path.Nodes[i].DurationMs)
+		path.Nodes[i].Share = share(path.Nodes[i].DurationMs, total)
+	}
+	path.Duration = formatDuration(path.DurationMs)
+	path.Share = share(path.DurationMs, total)
+
+	return path
+}
+
+// Contains reports whether the stage, or the step of that stage when step is given, is on the path.
+func (c CriticalPath) Contains(stage string, step string) bool {
+	for _, node := range c.Nodes {
+		if node.Stage == stage && (step == "" || node.Step == step) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeDuration(node models.GraphNode) int64 {
+	if node.EndMs <= node.StartMs {
+		return 0
+	}
+	return node.EndMs - node.StartMs
+}
+
+func share(part int64, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	value := float64(part) * 100 / float64(total)
+	if value > 100 {
+		return 100
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+// generators/dora.go
+package htmlgenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+	"time"
+)
+
+// Names of the embedded DORA report templates.
+const (
+	DoraTemplate         = "dora.html"
+	DoraMarkdownTemplate = "dora.md"
+)
+
+// DoraData is the data contract handed to the DORA templates.
+type DoraData struct {
+	models.DoraReport
+	WindowStart string // window start formatted as "Jan 02 2006"
+	WindowEnd   string // window end formatted as "Jan 02 2006"
+	Theme       Theme  // palette, fonts and logo; .Theme.CSS renders them as a style sheet
+}
+
+// NewDoraData formats a DORA report for rendering.
+func NewDoraData(report models.DoraReport) DoraData {
+	return DoraData{
+		DoraReport:  report,
+		WindowStart: time.UnixMilli(report.WindowStartMs).UTC().Format("Jan 02 2006"),
+		WindowEnd:   time.UnixMilli(report.WindowEndMs).UTC().Format("Jan 02 2006"),
+	}
+}
+
+// GenerateDoraHTML renders the DORA metrics as an HTML page.
+func GenerateDoraHTML(report models.DoraReport, opts Options) (string, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating DORA metrics report...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	data := NewDoraData(report)
+	data.Theme = opts.ReportTheme()
+
+	tmpl, err := LoadTemplate(DoraTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// GenerateDoraMarkdown renders the DORA metrics as Markdown.
+func GenerateDoraMarkdown(report models.DoraReport, opts Options) (string, error) {
+	tmpl, err := loadTextTemplate(DoraMarkdownTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, NewDoraData(report)); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// GenerateDoraJSON returns the DORA report as indented JSON.
+func GenerateDoraJSON(report models.DoraReport) ([]byte, error) {
+	output, err := json.MarshalIndent(report, "", "  ")
+	return append(output, '\n'), err
+}
+
+// levelClass maps a DORA performance level to the status class used to color it.
+func levelClass(level string) string {
+	switch level {
+	case "Elite", "High":
+		return "Success"
+	case "Medium":
+		return "AsyncWaiting"
+	case "Low":
+		return "Failed"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,128 @@
+// generators/failures.go
+package htmlgenerator
+
+import (
+	"pipeline-html-generator/internal/models"
+	"slices"
+	"sort"
+)
+
+// UnknownFailureType groups failures that Harness reported without a failure type.
+const UnknownFailureType = "UNKNOWN"
+
+// Failure describes a failed step, or a step whose error was ignored by a failure strategy.
+type Failure struct {
+	Stage        string
+	Step         string
+	Status       string
+	Message      string
+	FailureTypes []string
+	Ignored      bool
+}
+
+// FailureGroup collects the failures sharing a failure type, e.g. TIMEOUT_ERROR.
+type FailureGroup struct {
+	Type     string
+	Failures []Failure
+}
+
+// CollectFailures returns every failed or errored-but-ignored step in stage order.
+func CollectFailures(pipeline models.Pipeline) []Failure {
+	var failures []Failure
+	for _, stage := range pipeline.Stages {
+		for _, step := range stage.Steps {
+			failed := isFailedStatus(step.Status)
+			if !failed && step.FailureInfo.Message == "" {
+				continue
+			}
+			message := step.FailureInfo.Message
+			if message == "" {
+				message = step.Message
+			}
+			status := step.Status
+			if !failed {
+				status = "Ignored"
+			}
+			failures = append(failures, Failure{
+				Stage:        stage.Name,
+				Step:         step.Name,
+				Status:       status,
+				Message:      message,
+				FailureTypes: step.FailureInfo.FailureTypeList,
+				Ignored:      !failed,
+			})
+		}
+	}
+	return failures
+}
+
+// GroupFailures groups failures by failure type, largest group first. A step
+// with several failure types is listed under each of them.
+func GroupFailures(failures []Failure) []FailureGroup {
+	var groups []FailureGroup
+	index := map[string]int{}
+	for _, failure := range failures {
+		types := failure.FailureTypes
+		if len(types) == 0 {
+			types = []string{UnknownFailureType}
+		}
+		for _, failureType := range types {
+			i, ok := index[failureType]
+			if !ok {
+				i = len(groups)
+				index[failureType] = i
+				groups = append(groups, FailureGroup{Type: failureType})
+			}
+			groups[i].Failures = append(groups[i].Failures, failure)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Failures) != len(groups[j].Failures) {
+			return len(groups[i].Failures) > len(groups[j].Failures)
+		}
+		return groups[i].Type < groups[j].Type
+	})
+	return groups
+}
+
+// FailedSteps returns "Stage/Step" for every step that failed the pipeline. Ignored errors are left out.
+func FailedSteps(failures []Failure) []string {
+	var steps []string
+	for _, failure := range failures {
+		if !failure.Ignored {
+			steps = append(steps, failure.Stage+"/"+failure.Step)
+		}
+	}
+	return steps
+}
+
+// FailedStages returns the distinct stages holding a step that failed the pipeline, in stage order.
+func FailedStages(failures []Failure) []string {
+	var stages []string
+	for _, failure := range failures {
+		if !failure.Ignored && !slices.Contains(stages, failure.Stage) {
+			stages = append(stages, failure.Stage)
+		}
+	}
+	return stages
+}
+
+// FailureTypes returns the distinct failure types of the steps that failed the pipeline.
+func FailureTypes(failures []Failure) []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, failure := range failures {
+		if failure.Ignored {
+			continue
+		}
+		for _, failureType := range failure.FailureTypes {
+			if !seen[failureType] {
+				seen[failureType] = true
+				types = append(types, failureType)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
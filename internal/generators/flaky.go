@@ -0,0 +1,46 @@
+// generators/flaky.go
+package htmlgenerator
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// FlakyReportTemplate is the standalone flaky-steps report.
+const FlakyReportTemplate = "flaky.html"
+
+// FlakySteps lists the steps flagged as flaky, most flaky first.
+type FlakySteps []models.FlakyStep
+
+// Find returns the flaky step matching stage and step, or nil when the step is not flaky.
+func (f FlakySteps) Find(stage string, step string) *models.FlakyStep {
+	for i := range f {
+		if f[i].Stage == stage && f[i].Step == step {
+			return &f[i]
+		}
+	}
+	return nil
+}
+
+// GenerateFlakyReport renders the standalone report listing the flaky steps of a pipeline.
+func GenerateFlakyReport(pipeline models.Pipeline, opts Options) (string, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating flaky steps report...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	data := NewDashboardData(pipeline)
+	data.Theme = opts.ReportTheme()
+
+	tmpl, err := LoadTemplate(FlakyReportTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
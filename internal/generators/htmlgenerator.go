@@ -3,7 +3,6 @@ package htmlgenerator
 
 import (
 	"fmt"
-	"html/template"
 	"log"
 	"pipeline-html-generator/internal/models"
 	"sort"
@@ -12,15 +11,63 @@ import (
 )
 
 // GenerateDashboardHTML generates HTML for the dashboard based on a JSON structure.
-func GenerateDashboardHTML(pipeline models.Pipeline) (string, error) {
+// opts.Mode chooses between the rich dashboard and the email safe layout.
+func GenerateDashboardHTML(pipeline models.Pipeline, opts Options) (string, error) {
 	fmt.Println("|---------------------------------------------")
 	fmt.Println("| \033[1;36mGenerating dashboard...\033[0m")
 	fmt.Println("|---------------------------------------------")
 
-	// fmt.Println("Pipeline: ", pipeline)
+	data := NewDashboardData(pipeline)
+	data.Theme = opts.ReportTheme()
+
+	name, err := opts.PipelineTemplate()
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := LoadTemplate(name, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var resultHTML strings.Builder
+	err = tmpl.Execute(&resultHTML, data)
+	if err != nil {
+		return "", err
+	}
+
+	return resultHTML.String(), nil
+}
+
+// DashboardData is the data contract handed to the dashboard template.
+// Custom templates passed with --template can rely on every field below.
+type DashboardData struct {
+	Name          string             // pipeline name
+	Status        string             // Harness status of the execution, e.g. Success or Failed
+	StartedTime   string             // start time formatted as "Jan 02 15:04:05 MST"
+	Duration      string             // total duration as a Go duration string, e.g. "4m12s"
+	StageCount    int                // number of rendered stages
+	StepCount     int                // number of rendered steps
+	Message       string             // pipeline level error message, if any
+	Stages        []models.Stage     // stages sorted by start time, steps sorted inside each stage
+	ExecutionLink string             // URL of the execution in the Harness UI
+	ExecutionId   string             // Harness plan execution ID
+	Theme         Theme              // palette, fonts and logo; .Theme.CSS renders them as a style sheet
+	Failures      []Failure          // failed and errored-but-ignored steps in stage order
+	FailureGroups []FailureGroup     // the same failures grouped by failure type, largest group first
+	CriticalPath  CriticalPath       // stages and steps that determined the total duration
+	Comparison    *models.Comparison // differences with the previous execution, nil unless compare mode is on
+	FlakySteps    FlakySteps         // steps flagged as flaky; .FlakySteps.Find stage step returns one or nil
+	Trend         *models.Trend      // durations and outcomes of recent executions, nil unless trend is on
+	WaitTime      WaitTime           // waiting versus running time, with the waiting time per reason
+	Budgets       BudgetBreaches     // duration budgets exceeded; .Budgets.Find stage step returns one or nil
+}
+
+// NewDashboardData sorts and formats a pipeline for rendering. The pipeline
+// passed in is left untouched so it can be handed to other generators.
+func NewDashboardData(pipeline models.Pipeline) DashboardData {
+	pipeline.Stages = copyStages(pipeline.Stages)
 
 	const customDateFormat = "2006-01-02 15:04:05 -0700 MST"
-	var err error
 	startedTime, err := time.Parse(customDateFormat, pipeline.StartedTime)
 
 	if err != nil {
@@ -133,187 +180,37 @@ func GenerateDashboardHTML(pipeline models.Pipeline) (string, error) {
 
 	}
 
-	const htmlTemplate = `
-	<!DOCTYPE html>
-	<html>
-	<head>
-		<style>
-		body {
-			font-family: Arial, sans-serif;
-			margin: 0;
-			background-color: #f0f0f0;
-			display: flex;
-			flex-direction: column;
-			align-items: center;
-		}
-		.pipeline-container {
-			background-color: #fff;
-			border-radius: 10px;
-			box-shadow: 0 0 10px rgba(0,0,0,0.1);
-			overflow-x: auto;
-			max-width: 90%;
-			max-height: 120vh;
-			margin: 10px 20px;
-			padding: 20px;
-			height: fit-content;
-		}
-		.pipeline-title {
-			background-color: #00ABE3;
-			color: white;
-			padding: 20px;
-			text-align: center;
-			font-size: 20px; /* Adjusted font size */
-			border-radius: 10px 10px 0 0;
-			margin: -20px -20px 20px -20px;
-		}
-		.pipeline-info {
-			font-size: 16px;
-			color: #555;
-			padding: 20px; /* Increased padding */
-			background-color: #f8f8f8;
-			border-bottom: 1px solid #ccc;
-		}
-		.stage-container {
-			display: flex;
-			flex-direction: row;
-			align-items: flex-start;
-			overflow-x: auto;
-			overflow-y: auto;
-			padding: 10px;
-			max-height: 110vh;
-		}
-		.stage {
-			background-color: #f8f8f8;
-			border: 1px solid #ccc;
-			border-radius: 5px;
-			padding: 2px 8px 8px 8px; /* Adjusted padding accordingly */
-			margin: 0 5px;
-			box-shadow: 0 0 5px rgba(0,0,0,0.1);
-			flex: 0 1 auto;
-			width: 200px;  /* Increased width to accommodate the full width of steps */
-			min-width: 200px; /* Adjusted min-width accordingly */
-			max-width: 200px;
-			font-size: 14px; /* Maintained font size */
-		}
-		.step-container {
-			display: block;
-			align-items: flex-start;
-			width: 90%; /* Set to 100% to occupy full width of the stage */
-			max-width: 100%; /* Set to 100% to prevent horizontal stretching */
-			font-size: 12px; /* Consider increasing if text appears too small */
-		}
-		.step {
-			text-align: center;
-			display: block; /* Ensures it takes the full width available and stacks vertically */
-			background-color: #fff;
-			border: 1px solid #ccc;
-			border-radius: 5px;
-			padding: 8px 8px 8px 8px; /* Adjusted padding accordingly */
-			margin: 5px 0; /* Maintaining vertical margins */
-			box-shadow: 0 0 5px rgba(0,0,0,0.1);
-			width: 100%; /* Set to 100% to occupy the full width of the step container */
-			max-width: 100%; /* Set to 100% to prevent horizontal stretching */
-		}
-		.center {
-			text-align: center;
-		}
-
-		h4.center {
-			margin-top: 0;  /* remove the top margin */
-			margin-bottom: 0;  /* remove the bottom margin */
-			text-align: center;
-		}
-		
-		.green {
-			background-color: rgba(40, 167, 69, 0.1);
-		}
-		.red {
-			background-color: rgba(203, 36, 49, 0.1);
-		}
-		.orange {
-			background-color: rgba(227, 98, 9, 0.1);
-		}
-        .Success {
-            background-color: rgba(76, 175, 80, 0.5); /* Green with Transparency */
-        }
-        .Failed {
-            background-color: rgba(255, 87, 51, 0.5); /* Red with Transparency */
-        }
-        .Skipped {
-            background-color: rgba(169, 169, 169, 0.5); /* Gray with Transparency */
-        }
-		.Aborted {
-			background-color: rgba(255, 87, 51, 0.5); /* Red with Transparency */
-		}
-		.Running {
-			/* Blue with Transparency */
-			background-color: rgba(0, 123, 255, 0.5);
-		}
-		.AsyncWaiting {
-			/* yellow with Transparency */
-			background-color: rgba(255, 193, 7, 0.5);
-		}
-
-		</style>
-	</head>
-	<body>
-	<div class="pipeline-container">
-		<div class="pipeline-title">{{ .Name }} - Status: {{ .Status }}</div>
-		<div class="pipeline-info">
-			Started Time: {{ .StartedTime }}<br>
-			Duration: {{ .Duration }}<br>
-			Stage Count: {{ .StageCount }}<br>
-			Step Count: {{ .StepCount }}<br>
-			{{ if .Message }}Error: {{ .Message }}{{ end }}
-			ExecutionLink: <a href="{{ .ExecutionLink }}">Click Here!</a>
-		</div>
-		<div class="stage-container">
-			{{ range .Stages }}
-			<div class="stage">
-				<h4>{{ .Name }}</h4>
-				<p>Duration: {{ .Duration }}</p>
-				<div class="step-container">
-					{{ range .Steps }}
-					<div class="step {{ .Status }}">
-						<h4 class="center">{{ .Name }}</h4>
-						{{ if ne .Status "Success" }}<br>Status: {{ .Status }}<br>{{ end }}
-						{{ if ne .Status "Skipped" }}<br>Duration: {{ .Duration }}{{ end }}
-						{{ if .Message }}<p>Error:</p><b>{{ .Message }}</b>{{ end }}
-						{{ if eq .Status "Failed" }}<p>Failure Types:</p><b>{{ range .FailureInfo.FailureTypeList }}</p>{{ . }}</b> {{ end }}{{ end }}
-					</div>
-					{{ end }}
-				</div>
-			</div>
-			{{ end }}
-		</div>
-	</div>
-	</body>
-	</html>
-	`
-
-	dataMap := map[string]interface{}{
-		"Name":          pipeline.Name,
-		"Status":        pipeline.Status,
-		"StartedTime":   pipeline.StartedTime,
-		"Duration":      pipeline.Duration,
-		"StageCount":    pipeline.StageCount,
-		"StepCount":     pipeline.StepCount,
-		"Message":       pipeline.Message,
-		"Stages":        pipeline.Stages,
-		"ExecutionLink": pipeline.ExecutionLink,
-		"ExecutionId":   pipeline.ExecutionId,
+	failures := CollectFailures(pipeline)
+	criticalPath := ComputeCriticalPath(pipeline)
+
+	return DashboardData{
+		Name:          pipeline.Name,
+		Status:        pipeline.Status,
+		StartedTime:   pipeline.StartedTime,
+		Duration:      pipeline.Duration,
+		StageCount:    pipeline.StageCount,
+		StepCount:     pipeline.StepCount,
+		Message:       pipeline.Message,
+		Stages:        pipeline.Stages,
+		ExecutionLink: pipeline.ExecutionLink,
+		ExecutionId:   pipeline.ExecutionId,
+		Failures:      failures,
+		FailureGroups: GroupFailures(failures),
+		CriticalPath:  criticalPath,
+		Comparison:    pipeline.Comparison,
+		FlakySteps:    FlakySteps(pipeline.FlakySteps),
+		Trend:         pipeline.Trend,
+		WaitTime:      ComputeWaitTime(pipeline),
+		Budgets:       BudgetBreaches(pipeline.BudgetBreaches),
 	}
+}
 
-	tmpl, err := template.New("dashboard").Parse(htmlTemplate)
-	if err != nil {
-		return "", err
+// copyStages returns a deep copy of stages so sorting and formatting never leak back to the caller.
+func copyStages(stages []models.Stage) []models.Stage {
+	copied := make([]models.Stage, len(stages))
+	for i, stage := range stages {
+		copied[i] = stage
+		copied[i].Steps = append([]models.Step(nil), stage.Steps...)
 	}
-
-	var resultHTML strings.Builder
-	err = tmpl.Execute(&resultHTML, dataMap)
-	if err != nil {
-		return "", err
-	}
-
-	return resultHTML.String(), nil
+	return copied
 }
@@ -0,0 +1,136 @@
+// generators/junit.go
+package htmlgenerator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+	"time"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite maps a pipeline stage.
+type JUnitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []JUnitProperty `xml:"properties>property,omitempty"`
+	TestCases  []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitProperty is a name/value pair attached to a test suite.
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase maps a pipeline step.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure carries the step failure message and its Harness failure types.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// GenerateJUnitXML renders every stage as a testsuite and every step as a testcase.
+func GenerateJUnitXML(pipeline models.Pipeline) ([]byte, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating JUnit report...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	data := NewDashboardData(pipeline)
+	report := JUnitTestSuites{
+		Name: pipeline.Name,
+		Time: junitSeconds(pipeline.StartMs, pipeline.EndMs),
+	}
+
+	for _, stage := range data.Stages {
+		suite := JUnitTestSuite{
+			Name: stage.Name,
+			Time: junitSeconds(stage.StartMs, stage.EndMs),
+			Properties: []JUnitProperty{
+				{Name: "status", Value: stage.Status},
+				{Name: "module", Value: stage.Module},
+				{Name: "executionId", Value: pipeline.ExecutionId},
+			},
+		}
+		if stage.StartMs > 0 {
+			suite.Timestamp = time.UnixMilli(stage.StartMs).UTC().Format("2006-01-02T15:04:05")
+		}
+
+		for _, step := range stage.Steps {
+			testCase := JUnitTestCase{
+				Name:      step.Name,
+				ClassName: pipeline.Name + "." + stage.Name,
+				Time:      junitSeconds(step.StartMs, step.EndMs),
+			}
+			switch {
+			case isFailedStatus(step.Status):
+				message := step.FailureInfo.Message
+				if message == "" {
+					message = step.Message
+				}
+				failureType := strings.Join(step.FailureInfo.FailureTypeList, ",")
+				if failureType == "" {
+					failureType = step.Status
+				}
+				testCase.Failure = &JUnitFailure{Message: message, Type: failureType, Text: message}
+				suite.Failures++
+			case step.Status == "Skipped":
+				testCase.Skipped = &struct{}{}
+				suite.Skipped++
+			case step.FailureInfo.Message != "":
+				testCase.SystemOut = "Ignored error: " + step.FailureInfo.Message
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+			suite.Tests++
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+
+	output, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(output, '\n')...), nil
+}
+
+// isFailedStatus reports whether a Harness status counts as a failure.
+func isFailedStatus(status string) bool {
+	class := statusClass(status)
+	return class == "Failed" || class == "Aborted"
+}
+
+func junitSeconds(startMs int64, endMs int64) string {
+	if startMs <= 0 || endMs < startMs {
+		return "0.000"
+	}
+	return fmt.Sprintf("%.3f", float64(endMs-startMs)/1000)
+}
@@ -0,0 +1,122 @@
+// generators/markdown.go
+package htmlgenerator
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+	"text/template"
+)
+
+// Names of the embedded plain text templates.
+const (
+	MarkdownTemplate = "pipeline.md"
+	TextTemplate     = "pipeline.txt"
+)
+
+var statusEmojis = map[string]string{
+	"Success":      "✅",
+	"Failed":       "❌",
+	"Aborted":      "⛔",
+	"Skipped":      "⏭️",
+	"Running":      "🔄",
+	"AsyncWaiting": "⏳",
+}
+
+// GenerateMarkdown renders the pipeline as Markdown for pull request comments and wiki pages.
+// It uses the same data preparation as GenerateDashboardHTML.
+func GenerateMarkdown(pipeline models.Pipeline, opts Options) (string, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating markdown report...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	data := NewDashboardData(pipeline)
+
+	tmpl, err := loadTextTemplate(MarkdownTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// GenerateText renders the pipeline as a plain text summary.
+func GenerateText(pipeline models.Pipeline, opts Options) (string, error) {
+	tmpl, err := loadTextTemplate(TextTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, NewDashboardData(pipeline)); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// loadTextTemplate loads a text/template with the same override rules as LoadTemplate.
+func loadTextTemplate(name string, opts Options) (*template.Template, error) {
+	source, err := templateSource(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Funcs(markdownFuncs()).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func markdownFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatDuration": formatDuration,
+		"statusEmoji":    statusEmoji,
+		"mdCell":         markdownCell,
+		"mdCode":         markdownCode,
+		"percent":        percent,
+		"signedDuration": signedDuration,
+		"signedPercent":  signedPercent,
+		"join":           strings.Join,
+		"lower":          strings.ToLower,
+		"upper":          strings.ToUpper,
+	}
+}
+
+// statusEmoji returns the emoji shown next to a status in Markdown reports.
+func statusEmoji(status string) string {
+	if emoji, ok := statusEmojis[statusClass(status)]; ok {
+		return emoji
+	}
+	return "⚠️"
+}
+
+// markdownCell makes text safe to use inside a single Markdown table cell or line.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	text = strings.ReplaceAll(text, "\r", "")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// markdownCode wraps the first non-empty text in a fenced code block that the text cannot close.
+func markdownCode(texts ...string) string {
+	var text string
+	for _, candidate := range texts {
+		if strings.TrimSpace(candidate) != "" {
+			text = candidate
+			break
+		}
+	}
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence
+}
@@ -0,0 +1,194 @@
+// generators/minify.go
+package htmlgenerator
+
+import (
+	"bytes"
+	"strings"
+)
+
+// blockElements do not render the whitespace around them, so whitespace-only
+// text next to one of their tags can be dropped.
+var blockElements = map[string]bool{
+	"!doctype": true, "html": true, "head": true, "body": true, "meta": true, "title": true, "link": true,
+	"style": true, "script": true, "div": true, "p": true, "br": true, "hr": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "ul": true, "ol": true, "li": true, "table": true,
+	"thead": true, "tbody": true, "tfoot": true, "tr": true, "td": true, "th": true, "section": true,
+	"header": true, "footer": true, "details": true, "summary": true,
+}
+
+// MinifyHTML shrinks a rendered document without changing how it displays.
+// Comments are dropped, except Outlook conditional comments, runs of
+// whitespace collapse to a single space and disappear next to block tags,
+// style sheets are collapsed the same way outside strings, and the content
+// of pre, textarea and script elements is kept byte for byte.
+func MinifyHTML(document []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(document))
+
+	previous := "" // name of the last tag written, e.g. "td" or "/tr"
+	for i := 0; i < len(document); {
+		switch {
+		case bytes.HasPrefix(document[i:], []byte("<!--")):
+			end := bytes.Index(document[i+4:], []byte("-->"))
+			if end < 0 {
+				out.Write(document[i:])
+				return out.Bytes()
+			}
+			comment := document[i : i+4+end+3]
+			if bytes.HasPrefix(comment, []byte("<!--[if")) || bytes.HasPrefix(comment, []byte("<!--<![endif]")) {
+				out.Write(comment)
+			}
+			i += len(comment)
+
+		case isTag(document[i:]):
+			tag, length := minifyTag(document[i:])
+			out.Write(tag)
+			i += length
+			previous = tagName(tag)
+
+			if previous == "style" || previous == "pre" || previous == "textarea" || previous == "script" {
+				end := indexFold(document[i:], "</"+previous)
+				if end < 0 {
+					end = len(document) - i
+				}
+				if previous == "style" {
+					writeCollapsed(&out, document[i:i+end], true)
+				} else {
+					out.Write(document[i : i+end])
+				}
+				i += end
+			}
+
+		default:
+			end := bytes.IndexByte(document[i+1:], '<') + 1
+			if end == 0 {
+				end = len(document) - i
+			}
+			text := document[i : i+end]
+			i += end
+			if len(bytes.TrimLeft(text, " \t\n\r\f")) == 0 && (out.Len() == 0 || isBlockTag(previous) || isBlockTag(tagName(document[i:]))) {
+				continue
+			}
+			writeCollapsed(&out, text, false)
+		}
+	}
+	return bytes.TrimSpace(out.Bytes())
+}
+
+// isTag reports whether document starts with a tag rather than a literal "<".
+func isTag(document []byte) bool {
+	if len(document) < 2 || document[0] != '<' {
+		return false
+	}
+	c := document[1]
+	return c == '/' || c == '!' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// tagName returns the lower case name of the tag starting document, with the
+// slash of closing tags, or "" when document does not start with a tag.
+func tagName(document []byte) string {
+	if !isTag(document) {
+		return ""
+	}
+	end := bytes.IndexAny(document[1:], " \t\n\r\f/>")
+	if document[1] == '/' {
+		end = bytes.IndexAny(document[2:], " \t\n\r\f>") + 1
+	}
+	if end <= 0 {
+		return strings.ToLower(string(document[1:]))
+	}
+	return strings.ToLower(string(document[1 : 1+end]))
+}
+
+func isBlockTag(name string) bool {
+	return blockElements[strings.TrimPrefix(name, "/")]
+}
+
+// minifyTag collapses the whitespace between the attributes of the tag at the
+// start of document, leaving quoted values untouched. It returns the minified
+// tag and the length of the original one.
+func minifyTag(document []byte) ([]byte, int) {
+	var tag bytes.Buffer
+	var quote byte
+	space := false
+	for i := 0; i < len(document); i++ {
+		c := document[i]
+		switch {
+		case quote != 0:
+			tag.WriteByte(c)
+			if c == quote {
+				quote = 0
+			}
+		case isSpace(c):
+			space = true
+		case c == '>':
+			tag.WriteByte(c)
+			return tag.Bytes(), i + 1
+		default:
+			if space && c != '/' && c != '=' && !bytes.HasSuffix(tag.Bytes(), []byte("=")) {
+				tag.WriteByte(' ')
+			}
+			space = false
+			if c == '"' || c == '\'' {
+				quote = c
+			}
+			tag.WriteByte(c)
+		}
+	}
+	return tag.Bytes(), len(document)
+}
+
+// writeCollapsed writes text with every run of whitespace replaced by a single
+// space. With css, quoted strings are copied as is and comments are dropped.
+func writeCollapsed(out *bytes.Buffer, text []byte, css bool) {
+	space := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if isSpace(c) {
+			space = true
+			continue
+		}
+		if css && bytes.HasPrefix(text[i:], []byte("/*")) {
+			end := bytes.Index(text[i+2:], []byte("*/"))
+			if end < 0 {
+				break
+			}
+			i += end + 3
+			space = true
+			continue
+		}
+		if space {
+			out.WriteByte(' ')
+			space = false
+		}
+		switch {
+		case css && (c == '"' || c == '\''):
+			end := bytes.IndexByte(text[i+1:], c)
+			if end < 0 {
+				out.Write(text[i:])
+				return
+			}
+			out.Write(text[i : i+end+2])
+			i += end + 1
+		default:
+			out.WriteByte(c)
+		}
+	}
+	if space {
+		out.WriteByte(' ')
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// indexFold is bytes.Index ignoring the case of an ASCII needle.
+func indexFold(haystack []byte, needle string) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if bytes.EqualFold(haystack[i:i+len(needle)], []byte(needle)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,90 @@
+package htmlgenerator
+
+import "testing"
+
+func TestMinifyHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name:     "quoted > in attributes",
+			document: `<a  title="a > b"   href='x>y' >link</a>`,
+			want:     `<a title="a > b" href='x>y'>link</a>`,
+		},
+		{
+			name:     "attribute whitespace",
+			document: "<img\n\tsrc = \"a  b\"\n\talt=x  />",
+			want:     `<img src="a  b" alt=x/>`,
+		},
+		{
+			name:     "pre passthrough",
+			document: "<div>\n  <pre>  line 1\n\n    line 2  </pre>\n</div>",
+			want:     "<div><pre>  line 1\n\n    line 2  </pre></div>",
+		},
+		{
+			name:     "pre closed in upper case",
+			document: "<PRE> a  b </PRE>  <p> c </p>",
+			want:     "<PRE> a  b </PRE><p> c </p>",
+		},
+		{
+			name:     "script passthrough",
+			document: "<script>\n  if (a  <  b) { x = '<!-- no -->  y' }\n</script>",
+			want:     "<script>\n  if (a  <  b) { x = '<!-- no -->  y' }\n</script>",
+		},
+		{
+			name:     "textarea passthrough",
+			document: "<textarea name=t>  keep\n  me </textarea>",
+			want:     "<textarea name=t>  keep\n  me </textarea>",
+		},
+		{
+			name:     "comments dropped",
+			document: "<p>a<!-- note --> b</p>",
+			want:     "<p>a b</p>",
+		},
+		{
+			name:     "outlook conditional comments kept",
+			document: "<!--[if mso]>\n<table><tr><td>\n<![endif]-->\n<div>x</div>\n<!--[if mso]></td></tr></table><![endif]-->",
+			want:     "<!--[if mso]>\n<table><tr><td>\n<![endif]--><div>x</div><!--[if mso]></td></tr></table><![endif]-->",
+		},
+		{
+			name:     "downlevel-revealed conditional comments kept",
+			document: "<!--[if !mso]><!-->\n<span>web</span>\n<!--<![endif]-->",
+			want:     "<!--[if !mso]><!--> <span>web</span> <!--<![endif]-->",
+		},
+		{
+			name:     "inline spacing kept",
+			document: "<p>Status:\n    <b>Failed</b>\n    <span>in</span> <i>4m</i>.</p>",
+			want:     "<p>Status: <b>Failed</b> <span>in</span> <i>4m</i>.</p>",
+		},
+		{
+			name:     "block spacing dropped",
+			document: "<table>\n\t<tr>\n\t\t<td>a</td>\n\t\t<td>b</td>\n\t</tr>\n</table>",
+			want:     "<table><tr><td>a</td><td>b</td></tr></table>",
+		},
+		{
+			name:     "literal < in text",
+			document: "<p>1 < 2  and  3 > 2</p>",
+			want:     "<p>1 < 2 and 3 > 2</p>",
+		},
+		{
+			name:     "css strings and comments",
+			document: "<style>\n  a::after {\n    content: \"  two  spaces \";\n  }\n  /* dropped\n     comment */\n  b { font-family: 'My  Font'; }\n</style>",
+			want:     "<style> a::after { content: \"  two  spaces \"; } b { font-family: 'My  Font'; } </style>",
+		},
+		{
+			name:     "doctype and surrounding whitespace",
+			document: "\n\n<!DOCTYPE html>\n<html>\n<head>\n<title> T </title>\n</head>\n</html>\n",
+			want:     "<!DOCTYPE html><html><head><title> T </title></head></html>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(MinifyHTML([]byte(test.document))); got != test.want {
+				t.Errorf("MinifyHTML(%q)\n got: %q\nwant: %q", test.document, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// generators/renderer.go
+package htmlgenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"sort"
+	"strings"
+)
+
+// Renderer turns a pipeline into one output format.
+type Renderer interface {
+	// DefaultFilename is used when an output is requested without a path.
+	DefaultFilename() string
+	// Render returns the rendered document.
+	Render(pipeline models.Pipeline, opts Options) ([]byte, error)
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc struct {
+	Filename string
+	Fn       func(pipeline models.Pipeline, opts Options) ([]byte, error)
+}
+
+// DefaultFilename implements Renderer.
+func (r RendererFunc) DefaultFilename() string { return r.Filename }
+
+// Render implements Renderer.
+func (r RendererFunc) Render(pipeline models.Pipeline, opts Options) ([]byte, error) {
+	return r.Fn(pipeline, opts)
+}
+
+var renderers = map[string]Renderer{}
+
+// Register makes a renderer available under format. Registering a format twice replaces it.
+func Register(format string, renderer Renderer) {
+	renderers[strings.ToLower(format)] = renderer
+}
+
+// Lookup returns the renderer registered for format.
+func Lookup(format string) (Renderer, error) {
+	renderer, ok := renderers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q, available formats: %s", format, strings.Join(Formats(), ", "))
+	}
+	return renderer, nil
+}
+
+// Formats returns the registered format names.
+func Formats() []string {
+	var formats []string
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func init() {
+	Register("html", RendererFunc{Filename: "pipeline.html", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateDashboardHTML(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("email", RendererFunc{Filename: "pipeline-email.html", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		opts.Mode = ModeEmail
+		output, err := GenerateDashboardHTML(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("markdown", RendererFunc{Filename: "pipeline.md", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateMarkdown(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("text", RendererFunc{Filename: "pipeline.txt", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateText(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("junit", RendererFunc{Filename: "report.xml", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		return GenerateJUnitXML(pipeline)
+	}})
+	Register("badge", RendererFunc{Filename: "badge.svg", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateBadgeSVG(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("flaky", RendererFunc{Filename: "flaky-steps.html", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateFlakyReport(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("summary", RendererFunc{Filename: "pipeline-summary.html", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := GenerateSummaryHTML(pipeline, opts)
+		return []byte(output), err
+	}})
+	Register("slack", RendererFunc{Filename: "slack.json", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		return GenerateSlackMessage(pipeline)
+	}})
+	Register("teams", RendererFunc{Filename: "teams.json", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		return GenerateTeamsMessage(pipeline)
+	}})
+	Register("json", RendererFunc{Filename: "pipeline.json", Fn: func(pipeline models.Pipeline, opts Options) ([]byte, error) {
+		output, err := json.MarshalIndent(pipeline, "", "  ")
+		return append(output, '\n'), err
+	}})
+}
@@ -0,0 +1,170 @@
+// generators/slack.go
+package htmlgenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// SlackMessage is an incoming webhook payload using Block Kit.
+// Text is the fallback shown in notifications and by clients without blocks.
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is a header, section, divider or actions block.
+type SlackBlock struct {
+	Type     string         `json:"type"`
+	Text     *SlackText     `json:"text,omitempty"`
+	Fields   []SlackText    `json:"fields,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+// SlackText is a plain_text or mrkdwn text object.
+type SlackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// SlackElement is a button of an actions block.
+type SlackElement struct {
+	Type  string     `json:"type"`
+	Text  *SlackText `json:"text,omitempty"`
+	URL   string     `json:"url,omitempty"`
+	Style string     `json:"style,omitempty"`
+}
+
+// Block Kit limits the payload must stay within.
+const (
+	slackHeaderLimit  = 150
+	slackSectionLimit = 3000
+)
+
+// maxListedFailures is the number of failing steps listed in chat notifications.
+const maxListedFailures = 10
+
+// slackEmoji prefixes the header with the status of the execution.
+var slackEmoji = map[string]string{
+	"Success": ":white_check_mark:",
+	"Failed":  ":x:",
+	"Aborted": ":no_entry_sign:",
+	"Running": ":hourglass_flowing_sand:",
+}
+
+// NewSlackMessage renders the status, duration, trigger, failing steps and
+// execution link of a pipeline as a Block Kit message.
+func NewSlackMessage(pipeline models.Pipeline) SlackMessage {
+	data := NewDashboardData(pipeline)
+	emoji, ok := slackEmoji[statusClass(pipeline.Status)]
+	if !ok {
+		emoji = ":grey_question:"
+	}
+	title := fmt.Sprintf("%s %s: %s", emoji, pipeline.Name, pipeline.Status)
+
+	message := SlackMessage{Text: fmt.Sprintf("%s: %s", pipeline.Name, pipeline.Status)}
+	message.Blocks = append(message.Blocks, SlackBlock{
+		Type: "header",
+		Text: &SlackText{Type: "plain_text", Text: truncate(title, slackHeaderLimit), Emoji: true},
+	})
+
+	fields := []SlackText{
+		slackField("Duration", formatDuration(pipeline.Duration)),
+		slackField("Started", data.StartedTime),
+		slackField("Stages / Steps", fmt.Sprintf("%d / %d", pipeline.StageCount, pipeline.StepCount)),
+		slackField("Execution", pipeline.ExecutionId),
+	}
+	if trigger := describeTrigger(pipeline); trigger != "" {
+		fields = append(fields, slackField("Trigger", trigger))
+	}
+	message.Blocks = append(message.Blocks, SlackBlock{Type: "section", Fields: fields})
+
+	if pipeline.Message != "" {
+		message.Blocks = append(message.Blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: truncate("> "+slackEscape(pipeline.Message), slackSectionLimit)},
+		})
+	}
+
+	var failed []Failure
+	for _, failure := range data.Failures {
+		if !failure.Ignored {
+			failed = append(failed, failure)
+		}
+	}
+	if len(failed) > 0 {
+		var text strings.Builder
+		text.WriteString("*Failing steps*")
+		for i, failure := range failed {
+			if i == maxListedFailures {
+				fmt.Fprintf(&text, "\n_and %d more_", len(failed)-maxListedFailures)
+				break
+			}
+			fmt.Fprintf(&text, "\n• *%s / %s*", slackEscape(failure.Stage), slackEscape(failure.Step))
+			if failure.Message != "" {
+				fmt.Fprintf(&text, ": %s", slackEscape(truncate(firstLine(failure.Message), 200)))
+			}
+		}
+		message.Blocks = append(message.Blocks,
+			SlackBlock{Type: "divider"},
+			SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: truncate(text.String(), slackSectionLimit)}},
+		)
+	}
+
+	if pipeline.ExecutionLink != "" {
+		button := SlackElement{
+			Type: "button",
+			Text: &SlackText{Type: "plain_text", Text: "View execution"},
+			URL:  pipeline.ExecutionLink,
+		}
+		if statusClass(pipeline.Status) == "Failed" {
+			button.Style = "danger"
+		} else if statusClass(pipeline.Status) == "Success" {
+			button.Style = "primary"
+		}
+		message.Blocks = append(message.Blocks, SlackBlock{Type: "actions", Elements: []SlackElement{button}})
+	}
+
+	return message
+}
+
+// GenerateSlackMessage renders the Block Kit message as JSON.
+func GenerateSlackMessage(pipeline models.Pipeline) ([]byte, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating Slack message...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	output, err := json.MarshalIndent(NewSlackMessage(pipeline), "", "  ")
+	return append(output, '\n'), err
+}
+
+func slackField(label string, value string) SlackText {
+	if value == "" {
+		value = "-"
+	}
+	return SlackText{Type: "mrkdwn", Text: "*" + label + "*\n" + slackEscape(value)}
+}
+
+// describeTrigger describes how the execution started, e.g. "MANUAL by jane@example.com".
+func describeTrigger(pipeline models.Pipeline) string {
+	switch {
+	case pipeline.TriggerType != "" && pipeline.TriggeredBy != "":
+		return pipeline.TriggerType + " by " + pipeline.TriggeredBy
+	case pipeline.TriggerType != "":
+		return pipeline.TriggerType
+	}
+	return pipeline.TriggeredBy
+}
+
+// slackEscape escapes the characters that mrkdwn treats as control sequences.
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
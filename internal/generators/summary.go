@@ -0,0 +1,34 @@
+// generators/summary.go
+package htmlgenerator
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// SummaryTemplate is the compact HTML summary: the stage table and the failed
+// steps without charts or step details, small enough for output variables.
+const SummaryTemplate = "summary.html"
+
+// GenerateSummaryHTML renders the compact HTML summary of a pipeline.
+func GenerateSummaryHTML(pipeline models.Pipeline, opts Options) (string, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating HTML summary...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	data := NewDashboardData(pipeline)
+	data.Theme = opts.ReportTheme()
+
+	tmpl, err := LoadTemplate(SummaryTemplate, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
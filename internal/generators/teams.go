@@ -0,0 +1,190 @@
+// generators/teams.go
+package htmlgenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// TeamsMessage is a Teams incoming webhook payload carrying one Adaptive Card.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment wraps the card with its content type.
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is the root of an Adaptive Card.
+type AdaptiveCard struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []CardElement     `json:"body"`
+	Actions []CardAction      `json:"actions,omitempty"`
+	MSTeams map[string]string `json:"msteams,omitempty"`
+}
+
+// CardElement is a TextBlock, FactSet, Table, TableRow or TableCell.
+type CardElement struct {
+	Type             string        `json:"type"`
+	Text             string        `json:"text,omitempty"`
+	Size             string        `json:"size,omitempty"`
+	Weight           string        `json:"weight,omitempty"`
+	Color            string        `json:"color,omitempty"`
+	Wrap             bool          `json:"wrap,omitempty"`
+	Spacing          string        `json:"spacing,omitempty"`
+	Facts            []CardFact    `json:"facts,omitempty"`
+	Columns          []CardColumn  `json:"columns,omitempty"`
+	Rows             []CardElement `json:"rows,omitempty"`
+	Cells            []CardElement `json:"cells,omitempty"`
+	Items            []CardElement `json:"items,omitempty"`
+	FirstRowAsHeader bool          `json:"firstRowAsHeader,omitempty"`
+}
+
+// CardFact is a title/value pair of a FactSet.
+type CardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// CardColumn sets the relative width of a table column.
+type CardColumn struct {
+	Width int `json:"width"`
+}
+
+// CardAction is an Action.OpenUrl button.
+type CardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Style string `json:"style,omitempty"`
+}
+
+// teamsMaxStages keeps large pipelines under the 28 KB webhook payload limit.
+const teamsMaxStages = 30
+
+// teamsColors maps status classes to Adaptive Card text colors.
+var teamsColors = map[string]string{
+	"Success": "Good",
+	"Failed":  "Attention",
+	"Aborted": "Warning",
+	"Running": "Accent",
+}
+
+// NewTeamsMessage renders the status facts, stages table, failing steps and
+// execution link of a pipeline as an Adaptive Card.
+func NewTeamsMessage(pipeline models.Pipeline) TeamsMessage {
+	data := NewDashboardData(pipeline)
+	color := teamsColors[statusClass(pipeline.Status)]
+
+	card := AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.5",
+		MSTeams: map[string]string{"width": "Full"},
+	}
+	card.Body = append(card.Body, CardElement{
+		Type: "TextBlock", Text: fmt.Sprintf("%s: %s", pipeline.Name, pipeline.Status),
+		Size: "Large", Weight: "Bolder", Color: color, Wrap: true,
+	})
+
+	facts := []CardFact{
+		{Title: "Status", Value: pipeline.Status},
+		{Title: "Duration", Value: formatDuration(pipeline.Duration)},
+		{Title: "Started", Value: data.StartedTime},
+		{Title: "Stages / Steps", Value: fmt.Sprintf("%d / %d", pipeline.StageCount, pipeline.StepCount)},
+	}
+	if trigger := describeTrigger(pipeline); trigger != "" {
+		facts = append(facts, CardFact{Title: "Trigger", Value: trigger})
+	}
+	if pipeline.ExecutionId != "" {
+		facts = append(facts, CardFact{Title: "Execution", Value: pipeline.ExecutionId})
+	}
+	card.Body = append(card.Body, CardElement{Type: "FactSet", Facts: facts})
+
+	if pipeline.Message != "" {
+		card.Body = append(card.Body, CardElement{Type: "TextBlock", Text: pipeline.Message, Color: "Attention", Wrap: true})
+	}
+
+	if len(data.Stages) > 0 {
+		table := CardElement{
+			Type:             "Table",
+			Columns:          []CardColumn{{Width: 3}, {Width: 2}, {Width: 2}},
+			FirstRowAsHeader: true,
+			Rows:             []CardElement{teamsRow("", "Stage", "Status", "Duration")},
+		}
+		for i, stage := range data.Stages {
+			if i == teamsMaxStages {
+				table.Rows = append(table.Rows, teamsRow("", fmt.Sprintf("and %d more", len(data.Stages)-teamsMaxStages), "", ""))
+				break
+			}
+			table.Rows = append(table.Rows, teamsRow(teamsColors[statusClass(stage.Status)], stage.Name, stage.Status, strings.TrimSpace(stage.Duration)))
+		}
+		card.Body = append(card.Body, table)
+	}
+
+	var failed []string
+	for _, failure := range data.Failures {
+		if failure.Ignored {
+			continue
+		}
+		line := fmt.Sprintf("- **%s / %s**", failure.Stage, failure.Step)
+		if failure.Message != "" {
+			line += ": " + truncate(firstLine(failure.Message), 200)
+		}
+		failed = append(failed, line)
+	}
+	if len(failed) > maxListedFailures {
+		failed = append(failed[:maxListedFailures], fmt.Sprintf("- and %d more", len(failed)-maxListedFailures))
+	}
+	if len(failed) > 0 {
+		card.Body = append(card.Body,
+			CardElement{Type: "TextBlock", Text: "Failing steps", Weight: "Bolder", Spacing: "Medium"},
+			CardElement{Type: "TextBlock", Text: strings.Join(failed, "\n"), Wrap: true},
+		)
+	}
+
+	if pipeline.ExecutionLink != "" {
+		action := CardAction{Type: "Action.OpenUrl", Title: "View execution", URL: pipeline.ExecutionLink}
+		switch statusClass(pipeline.Status) {
+		case "Failed":
+			action.Style = "destructive"
+		case "Success":
+			action.Style = "positive"
+		}
+		card.Actions = []CardAction{action}
+	}
+
+	return TeamsMessage{
+		Type:        "message",
+		Attachments: []TeamsAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: card}},
+	}
+}
+
+// GenerateTeamsMessage renders the Adaptive Card message as JSON.
+func GenerateTeamsMessage(pipeline models.Pipeline) ([]byte, error) {
+	fmt.Println("|---------------------------------------------")
+	fmt.Println("| \033[1;36mGenerating Teams message...\033[0m")
+	fmt.Println("|---------------------------------------------")
+
+	output, err := json.MarshalIndent(NewTeamsMessage(pipeline), "", "  ")
+	return append(output, '\n'), err
+}
+
+// teamsRow builds a table row of text cells, colored when color is set.
+func teamsRow(color string, cells ...string) CardElement {
+	row := CardElement{Type: "TableRow"}
+	for _, cell := range cells {
+		row.Cells = append(row.Cells, CardElement{
+			Type:  "TableCell",
+			Items: []CardElement{{Type: "TextBlock", Text: cell, Color: color, Wrap: true}},
+		})
+	}
+	return row
+}
@@ -0,0 +1,245 @@
+// generators/templates.go
+package htmlgenerator
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Names of the default templates embedded in the binary.
+const (
+	DashboardTemplate    = "dashboard.html"
+	EmailTemplate        = "email.html"
+	CommitReportTemplate = "commit_report.html"
+)
+
+// HTML rendering modes.
+const (
+	// ModeRich is the default dashboard with flexbox layout and a style sheet.
+	ModeRich = "rich"
+	// ModeEmail renders a table based layout with inlined styles that survives
+	// email clients such as Outlook and Gmail.
+	ModeEmail = "email"
+)
+
+//go:embed templates/*.html templates/*.md templates/*.txt
+var defaultTemplates embed.FS
+
+// Options controls how reports are rendered.
+type Options struct {
+	// Mode selects the HTML renderer, ModeRich (default) or ModeEmail.
+	Mode string
+	// TemplatePath is a user html/template file replacing the template of the selected mode.
+	TemplatePath string
+	// TemplateDir is a directory of overrides. A file named like a default
+	// template (e.g. dashboard.html) replaces it, and every *.tmpl file is
+	// parsed as a partial so it can redefine blocks or add new templates.
+	TemplateDir string
+	// Theme styles the reports. The light theme is used when it is left empty.
+	Theme Theme
+	// Badge configures the SVG status badge.
+	Badge BadgeOptions
+}
+
+// ReportTheme returns the configured theme, falling back to the default one.
+func (o Options) ReportTheme() Theme {
+	if o.Theme.Name == "" && o.Theme.StatusColors == nil {
+		return builtinThemes[DefaultTheme]
+	}
+	return o.Theme
+}
+
+// PipelineTemplate returns the name of the pipeline template for the selected mode.
+func (o Options) PipelineTemplate() (string, error) {
+	switch o.Mode {
+	case "", ModeRich:
+		return DashboardTemplate, nil
+	case ModeEmail:
+		return EmailTemplate, nil
+	}
+	return "", fmt.Errorf("unknown html mode %q, expected %s or %s", o.Mode, ModeRich, ModeEmail)
+}
+
+// LoadTemplate returns the named template, applying any user overrides and partials from opts.
+func LoadTemplate(name string, opts Options) (*template.Template, error) {
+	source, err := templateSource(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+
+	if opts.TemplateDir == "" {
+		return tmpl, nil
+	}
+
+	partials, err := filepath.Glob(filepath.Join(opts.TemplateDir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, partial := range partials {
+		content, err := os.ReadFile(partial)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.New(filepath.Base(partial)).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("error parsing partial %s: %w", partial, err)
+		}
+	}
+
+	return tmpl, nil
+}
+
+func templateSource(name string, opts Options) (string, error) {
+	if pipelineTemplate, _ := opts.PipelineTemplate(); name == pipelineTemplate && opts.TemplatePath != "" {
+		content, err := os.ReadFile(opts.TemplatePath)
+		if err != nil {
+			return "", fmt.Errorf("error reading template %s: %w", opts.TemplatePath, err)
+		}
+		return string(content), nil
+	}
+
+	if opts.TemplateDir != "" {
+		content, err := os.ReadFile(filepath.Join(opts.TemplateDir, name))
+		if err == nil {
+			return string(content), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	content, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("unknown template %s", name)
+	}
+	return string(content), nil
+}
+
+// TemplateFuncs returns the helper functions available to every template.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatDuration": formatDuration,
+		"statusClass":    statusClass,
+		"link":           link,
+		"anchor":         anchor,
+		"css":            cssValue,
+		"solid":          solidColor,
+		"percent":        percent,
+		"signedDuration": signedDuration,
+		"signedPercent":  signedPercent,
+		"durationChart":  DurationChart,
+		"stageChart":     StageChart,
+		"outcomeChart":   OutcomeChart,
+		"levelClass":     levelClass,
+		"lower":          strings.ToLower,
+		"upper":          strings.ToUpper,
+		"join":           strings.Join,
+	}
+}
+
+// knownStatuses are the Harness statuses that have a matching CSS class.
+var knownStatuses = map[string]bool{
+	"Success":      true,
+	"Failed":       true,
+	"Skipped":      true,
+	"Aborted":      true,
+	"Running":      true,
+	"AsyncWaiting": true,
+}
+
+// statusClass maps a Harness status to the CSS class used by the templates.
+func statusClass(status string) string {
+	if knownStatuses[status] {
+		return status
+	}
+	if status == "Errored" || status == "Expired" || status == "ApprovalRejected" {
+		return "Failed"
+	}
+	return "Unknown"
+}
+
+// formatDuration renders a duration (time.Duration, Go duration string or
+// milliseconds) in a compact human readable form such as "1h 4m" or "12s".
+func formatDuration(value interface{}) string {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case int:
+		d = time.Duration(v) * time.Millisecond
+	case int64:
+		d = time.Duration(v) * time.Millisecond
+	case string:
+		parsed, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return v
+		}
+		d = parsed
+	default:
+		return fmt.Sprint(value)
+	}
+
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	switch {
+	case hours >= 24:
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// percent formats a percentage with one decimal, e.g. "42.5%".
+func percent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+// signedDuration formats a duration delta in milliseconds with its sign, e.g. "+1m 5s".
+func signedDuration(deltaMs int64) string {
+	if deltaMs < 0 {
+		return "-" + formatDuration(-deltaMs)
+	}
+	return "+" + formatDuration(deltaMs)
+}
+
+// signedPercent formats a percentage delta with its sign, e.g. "-12.5%".
+func signedPercent(value float64) string {
+	return fmt.Sprintf("%+.1f%%", value)
+}
+
+// link renders an anchor to an http(s) URL, or just the text when the URL is unusable.
+func link(href string, text string) template.HTML {
+	parsed, err := url.Parse(href)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return template.HTML(template.HTMLEscapeString(text))
+	}
+	return template.HTML(fmt.Sprintf(`<a href="%s">%s</a>`, template.HTMLEscapeString(parsed.String()), template.HTMLEscapeString(text)))
+}
+
+var anchorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// anchor turns a stage or step name into a value usable as an element id.
+func anchor(parts ...string) string {
+	return strings.Trim(anchorPattern.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-"), "-")
+}
@@ -0,0 +1,333 @@
+// generators/theme.go
+package htmlgenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the colors, fonts and logo applied to every HTML report.
+type Theme struct {
+	Name         string            `json:"name"`
+	Logo         string            `json:"logo"`
+	Font         string            `json:"font"`
+	Palette      Palette           `json:"palette"`
+	StatusColors map[string]string `json:"statusColors"`
+}
+
+// Palette lists the named colors used by the templates.
+type Palette struct {
+	Header     string `json:"header"`
+	HeaderText string `json:"headerText"`
+	Background string `json:"background"`
+	Surface    string `json:"surface"`
+	SurfaceAlt string `json:"surfaceAlt"`
+	Text       string `json:"text"`
+	Muted      string `json:"muted"`
+	Border     string `json:"border"`
+	Link       string `json:"link"`
+	Added      string `json:"added"`
+	Modified   string `json:"modified"`
+	Deleted    string `json:"deleted"`
+	Critical   string `json:"critical"`
+	Flaky      string `json:"flaky"`
+	Breach     string `json:"breach"`
+}
+
+// DefaultTheme is used when no theme is selected.
+const DefaultTheme = "light"
+
+var builtinThemes = map[string]Theme{
+	"light": {
+		Name: "light",
+		Font: "Arial, sans-serif",
+		Palette: Palette{
+			Header:     "#00ABE3",
+			HeaderText: "#ffffff",
+			Background: "#f0f0f0",
+			Surface:    "#ffffff",
+			SurfaceAlt: "#f8f8f8",
+			Text:       "#222222",
+			Muted:      "#555555",
+			Border:     "#cccccc",
+			Link:       "#0B5ED7",
+			Added:      "rgba(40, 167, 69, 0.1)",
+			Modified:   "rgba(227, 98, 9, 0.1)",
+			Deleted:    "rgba(203, 36, 49, 0.1)",
+			Critical:   "#E36209",
+			Flaky:      "#B08800",
+			Breach:     "#CB2431",
+		},
+		StatusColors: map[string]string{
+			"Success":      "rgba(76, 175, 80, 0.5)",
+			"Failed":       "rgba(255, 87, 51, 0.5)",
+			"Skipped":      "rgba(169, 169, 169, 0.5)",
+			"Aborted":      "rgba(255, 87, 51, 0.5)",
+			"Running":      "rgba(0, 123, 255, 0.5)",
+			"AsyncWaiting": "rgba(255, 193, 7, 0.5)",
+			"Unknown":      "rgba(169, 169, 169, 0.2)",
+		},
+	},
+	"dark": {
+		Name: "dark",
+		Font: "Arial, sans-serif",
+		Palette: Palette{
+			Header:     "#0B6E99",
+			HeaderText: "#f5f5f5",
+			Background: "#121212",
+			Surface:    "#1e1e1e",
+			SurfaceAlt: "#2a2a2a",
+			Text:       "#e6e6e6",
+			Muted:      "#a8a8a8",
+			Border:     "#3d3d3d",
+			Link:       "#6CB6FF",
+			Added:      "rgba(46, 160, 67, 0.25)",
+			Modified:   "rgba(210, 153, 34, 0.25)",
+			Deleted:    "rgba(248, 81, 73, 0.25)",
+			Critical:   "#F0883E",
+			Flaky:      "#D29922",
+			Breach:     "#F85149",
+		},
+		StatusColors: map[string]string{
+			"Success":      "rgba(46, 160, 67, 0.45)",
+			"Failed":       "rgba(248, 81, 73, 0.45)",
+			"Skipped":      "rgba(110, 118, 129, 0.45)",
+			"Aborted":      "rgba(248, 81, 73, 0.45)",
+			"Running":      "rgba(56, 139, 253, 0.45)",
+			"AsyncWaiting": "rgba(210, 153, 34, 0.45)",
+			"Unknown":      "rgba(110, 118, 129, 0.25)",
+		},
+	},
+	"high-contrast": {
+		Name: "high-contrast",
+		Font: "Verdana, Arial, sans-serif",
+		Palette: Palette{
+			Header:     "#000000",
+			HeaderText: "#FFFF00",
+			Background: "#000000",
+			Surface:    "#000000",
+			SurfaceAlt: "#000000",
+			Text:       "#FFFFFF",
+			Muted:      "#FFFFFF",
+			Border:     "#FFFFFF",
+			Link:       "#FFFF00",
+			Added:      "#005A00",
+			Modified:   "#7A4A00",
+			Deleted:    "#8B0000",
+			Critical:   "#FF00FF",
+			Flaky:      "#FFA500",
+			Breach:     "#FF0000",
+		},
+		StatusColors: map[string]string{
+			"Success":      "#006400",
+			"Failed":       "#B00000",
+			"Skipped":      "#4D4D4D",
+			"Aborted":      "#B00000",
+			"Running":      "#0033CC",
+			"AsyncWaiting": "#8A6D00",
+			"Unknown":      "#333333",
+		},
+	},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme returns the named built-in theme with the overrides from themeFile applied.
+// Only the fields present in the JSON file replace the built-in values.
+func LoadTheme(name string, themeFile string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	base, ok := builtinThemes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, available themes: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+
+	theme := base
+	theme.StatusColors = make(map[string]string, len(base.StatusColors))
+	for status, color := range base.StatusColors {
+		theme.StatusColors[status] = color
+	}
+
+	if themeFile == "" {
+		return theme, nil
+	}
+
+	content, err := os.ReadFile(themeFile)
+	if err != nil {
+		return Theme{}, fmt.Errorf("error reading theme file %s: %w", themeFile, err)
+	}
+	if err := json.Unmarshal(content, &theme); err != nil {
+		return Theme{}, fmt.Errorf("error parsing theme file %s: %w", themeFile, err)
+	}
+
+	for _, value := range append(theme.values(), theme.Font) {
+		if !safeCSSValue(value) {
+			return Theme{}, fmt.Errorf("theme file %s contains an unsupported CSS value %q", themeFile, value)
+		}
+	}
+
+	return theme, nil
+}
+
+// StatusColor returns the color configured for a status.
+func (t Theme) StatusColor(status string) string {
+	if color, ok := t.StatusColors[statusClass(status)]; ok {
+		return color
+	}
+	return t.StatusColors["Unknown"]
+}
+
+// CSS returns the theme as CSS custom properties plus one rule per status class.
+func (t Theme) CSS() template.CSS {
+	var css strings.Builder
+	css.WriteString(":root {\n")
+	for _, property := range []struct{ name, value string }{
+		{"font", t.Font},
+		{"header", t.Palette.Header},
+		{"header-text", t.Palette.HeaderText},
+		{"background", t.Palette.Background},
+		{"surface", t.Palette.Surface},
+		{"surface-alt", t.Palette.SurfaceAlt},
+		{"text", t.Palette.Text},
+		{"muted", t.Palette.Muted},
+		{"border", t.Palette.Border},
+		{"link", t.Palette.Link},
+		{"added", t.Palette.Added},
+		{"modified", t.Palette.Modified},
+		{"deleted", t.Palette.Deleted},
+		{"critical", t.Palette.Critical},
+		{"flaky", t.Palette.Flaky},
+		{"breach", t.Palette.Breach},
+	} {
+		if safeCSSValue(property.value) {
+			fmt.Fprintf(&css, "\t--%s: %s;\n", property.name, property.value)
+		}
+	}
+	css.WriteString("}\n")
+
+	var statuses []string
+	for status := range t.StatusColors {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if safeCSSValue(status) && safeCSSValue(t.StatusColors[status]) {
+			fmt.Fprintf(&css, ".%s { background-color: %s; }\n", status, t.StatusColors[status])
+		}
+	}
+
+	return template.CSS(css.String())
+}
+
+func (t Theme) values() []string {
+	values := []string{
+		t.Palette.Header, t.Palette.HeaderText, t.Palette.Background, t.Palette.Surface,
+		t.Palette.SurfaceAlt, t.Palette.Text, t.Palette.Muted, t.Palette.Border,
+		t.Palette.Link, t.Palette.Added, t.Palette.Modified, t.Palette.Deleted,
+		t.Palette.Critical, t.Palette.Flaky, t.Palette.Breach,
+	}
+	for status, color := range t.StatusColors {
+		values = append(values, status, color)
+	}
+	return values
+}
+
+// safeCSSValue reports whether value can be written into a style sheet without escaping it.
+func safeCSSValue(value string) bool {
+	lower := strings.ToLower(value)
+	if strings.Contains(lower, "url(") || strings.Contains(lower, "expression(") {
+		return false
+	}
+	return !strings.ContainsAny(value, ";{}<>\\@\n\r")
+}
+
+// cssValue exposes a theme value to templates that inline styles.
+func cssValue(value string) template.CSS {
+	if !safeCSSValue(value) {
+		return ""
+	}
+	return template.CSS(value)
+}
+
+var rgbaPattern = regexp.MustCompile(`^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+
+// solidColor flattens a color onto backdrop and returns it as #rrggbb. Email
+// clients such as Outlook ignore rgba(), so the email template only uses solid colors.
+func solidColor(color string, backdrop string) template.CSS {
+	r, g, b, alpha, ok := parseColor(color)
+	if !ok {
+		return cssValue(color)
+	}
+	if alpha < 1 {
+		br, bg, bb, _, ok := parseColor(backdrop)
+		if !ok {
+			br, bg, bb = 255, 255, 255
+		}
+		r = blend(r, br, alpha)
+		g = blend(g, bg, alpha)
+		b = blend(b, bb, alpha)
+	}
+	return template.CSS(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+}
+
+// opaqueColor drops the alpha channel of color, returning fallback when it cannot be parsed.
+func opaqueColor(color string, fallback string) string {
+	r, g, b, _, ok := parseColor(color)
+	if !ok {
+		return fallback
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+func parseColor(color string) (r, g, b int, alpha float64, ok bool) {
+	color = strings.TrimSpace(color)
+	if strings.HasPrefix(color, "#") {
+		hex := color[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return 0, 0, 0, 0, false
+		}
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return 0, 0, 0, 0, false
+		}
+		return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), 1, true
+	}
+
+	match := rgbaPattern.FindStringSubmatch(color)
+	if match == nil {
+		return 0, 0, 0, 0, false
+	}
+	r, _ = strconv.Atoi(match[1])
+	g, _ = strconv.Atoi(match[2])
+	b, _ = strconv.Atoi(match[3])
+	alpha = 1
+	if match[4] != "" {
+		alpha, _ = strconv.ParseFloat(match[4], 64)
+	}
+	if r > 255 || g > 255 || b > 255 || alpha > 1 {
+		return 0, 0, 0, 0, false
+	}
+	return r, g, b, alpha, true
+}
+
+func blend(foreground int, background int, alpha float64) int {
+	return int(float64(foreground)*alpha + float64(background)*(1-alpha) + 0.5)
+}
@@ -0,0 +1,196 @@
+// generators/trend.go
+package htmlgenerator
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"pipeline-html-generator/internal/models"
+	"strings"
+)
+
+// Size of the trend charts in pixels.
+const (
+	chartWidth  = 600
+	chartHeight = 160
+	chartLeft   = 56 // room for the duration axis labels
+	chartBottom = 20
+	chartTop    = 10
+)
+
+// stageColors tell stages apart in the stacked per-stage chart. They are used in order and repeat.
+var stageColors = []string{"#4E79A7", "#F28E2B", "#59A14F", "#E15759", "#76B7B2", "#EDC948", "#B07AA1", "#FF9DA7", "#9C755F", "#BAB0AC"}
+
+// chartStyle holds the solid theme colors used by the charts, so the SVG renders
+// the same in browsers and in email clients that ignore CSS variables.
+type chartStyle struct {
+	font  string
+	text  string
+	muted string
+	grid  string
+}
+
+func newChartStyle(theme Theme) chartStyle {
+	font := theme.Font
+	if !safeCSSValue(font) {
+		font = "sans-serif"
+	}
+	return chartStyle{
+		font:  html.EscapeString(font),
+		text:  string(solidColor(theme.Palette.Text, theme.Palette.Surface)),
+		muted: string(solidColor(theme.Palette.Muted, theme.Palette.Surface)),
+		grid:  string(solidColor(theme.Palette.Border, theme.Palette.Surface)),
+	}
+}
+
+// DurationChart renders the total duration of each execution as a bar colored by status.
+func DurationChart(trend *models.Trend, theme Theme) template.HTML {
+	if trend == nil || len(trend.Executions) == 0 {
+		return ""
+	}
+	style := newChartStyle(theme)
+
+	var maxMs int64
+	for _, point := range trend.Executions {
+		if point.DurationMs > maxMs {
+			maxMs = point.DurationMs
+		}
+	}
+
+	var svg strings.Builder
+	openChart(&svg, chartHeight, "Execution durations")
+	durationAxis(&svg, style, maxMs)
+	slot, barWidth := chartSlots(len(trend.Executions))
+	for i, point := range trend.Executions {
+		height := scale(point.DurationMs, maxMs)
+		x := chartLeft + float64(i)*slot + (slot-barWidth)/2
+		fmt.Fprintf(&svg, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`,
+			x, float64(chartHeight-chartBottom)-height, barWidth, height,
+			opaqueColor(theme.StatusColor(point.Status), "#9f9f9f"), html.EscapeString(pointTitle(point)))
+	}
+	svg.WriteString(`</svg>`)
+	return template.HTML(svg.String())
+}
+
+// StageChart renders the stage durations of each execution as stacked bars with a legend.
+func StageChart(trend *models.Trend, theme Theme) template.HTML {
+	if trend == nil || len(trend.Executions) == 0 || len(trend.Stages) == 0 {
+		return ""
+	}
+	style := newChartStyle(theme)
+
+	var maxMs int64
+	for _, point := range trend.Executions {
+		var total int64
+		for _, ms := range point.StageDurationsMs {
+			total += ms
+		}
+		if total > maxMs {
+			maxMs = total
+		}
+	}
+
+	const legendColumn, legendRow = 150, 16
+	perRow := (chartWidth - chartLeft) / legendColumn
+	legendRows := (len(trend.Stages) + perRow - 1) / perRow
+	height := chartHeight + legendRows*legendRow + 6
+
+	var svg strings.Builder
+	openChart(&svg, height, "Stage durations")
+	durationAxis(&svg, style, maxMs)
+	slot, barWidth := chartSlots(len(trend.Executions))
+	for i, point := range trend.Executions {
+		x := chartLeft + float64(i)*slot + (slot-barWidth)/2
+		y := float64(chartHeight - chartBottom)
+		for s, stage := range trend.Stages {
+			ms, ok := point.StageDurationsMs[stage]
+			if !ok {
+				continue
+			}
+			segment := scale(ms, maxMs)
+			y -= segment
+			fmt.Fprintf(&svg, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s (%s)</title></rect>`,
+				x, y, barWidth, segment, stageColors[s%len(stageColors)],
+				html.EscapeString(stage), formatDuration(ms), html.EscapeString(point.ExecutionId))
+		}
+	}
+	for s, stage := range trend.Stages {
+		x := chartLeft + (s%perRow)*legendColumn
+		y := chartHeight + (s/perRow)*legendRow
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, x, y, stageColors[s%len(stageColors)])
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="%s" font-size="11" fill="%s">%s</text>`,
+			x+14, y+9, style.font, style.text, html.EscapeString(truncate(stage, 20)))
+	}
+	svg.WriteString(`</svg>`)
+	return template.HTML(svg.String())
+}
+
+// OutcomeChart renders the pass/fail pattern as one square per execution, oldest first.
+func OutcomeChart(trend *models.Trend, theme Theme) template.HTML {
+	if trend == nil || len(trend.Executions) == 0 {
+		return ""
+	}
+
+	const size, gap = 16, 4
+	width := len(trend.Executions)*(size+gap) - gap
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Execution outcomes">`, width, size, width, size)
+	for i, point := range trend.Executions {
+		fmt.Fprintf(&svg, `<rect x="%d" y="0" width="%d" height="%d" rx="3" fill="%s"><title>%s</title></rect>`,
+			i*(size+gap), size, size, opaqueColor(theme.StatusColor(point.Status), "#9f9f9f"), html.EscapeString(pointTitle(point)))
+	}
+	svg.WriteString(`</svg>`)
+	return template.HTML(svg.String())
+}
+
+func openChart(svg *strings.Builder, height int, label string) {
+	fmt.Fprintf(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		chartWidth, height, chartWidth, height, label)
+}
+
+// durationAxis draws the horizontal grid lines labelled with durations up to maxMs.
+func durationAxis(svg *strings.Builder, style chartStyle, maxMs int64) {
+	for _, fraction := range []float64{0, 0.5, 1} {
+		y := float64(chartHeight-chartBottom) - fraction*float64(chartHeight-chartBottom-chartTop)
+		fmt.Fprintf(svg, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-width="1"/>`, chartLeft, y, chartWidth, y, style.grid)
+		fmt.Fprintf(svg, `<text x="%d" y="%.1f" text-anchor="end" font-family="%s" font-size="11" fill="%s">%s</text>`,
+			chartLeft-6, y+4, style.font, style.muted, formatDuration(int64(fraction*float64(maxMs))))
+	}
+	fmt.Fprintf(svg, `<text x="%d" y="%d" font-family="%s" font-size="11" fill="%s">oldest</text>`, chartLeft, chartHeight-4, style.font, style.muted)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="end" font-family="%s" font-size="11" fill="%s">latest</text>`, chartWidth, chartHeight-4, style.font, style.muted)
+}
+
+// chartSlots returns the horizontal space given to each execution and the width of its bar.
+func chartSlots(count int) (slot float64, barWidth float64) {
+	slot = float64(chartWidth-chartLeft) / float64(count)
+	barWidth = slot * 0.7
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	return slot, barWidth
+}
+
+// scale converts a duration to a bar height, keeping non-zero durations visible.
+func scale(ms int64, maxMs int64) float64 {
+	if ms <= 0 || maxMs <= 0 {
+		return 0
+	}
+	height := float64(ms) / float64(maxMs) * float64(chartHeight-chartBottom-chartTop)
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+func pointTitle(point models.TrendPoint) string {
+	return fmt.Sprintf("%s - %s - %s", point.ExecutionId, point.Status, formatDuration(point.DurationMs))
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,47 @@
+// generators/waits.go
+package htmlgenerator
+
+import (
+	"pipeline-html-generator/internal/models"
+	"sort"
+)
+
+// WaitTime splits the pipeline duration into waiting and running time.
+type WaitTime struct {
+	WaitMs    int64
+	Wait      string // formatted with formatDuration
+	RunMs     int64
+	Run       string  // formatted with formatDuration
+	WaitShare float64 // percentage of the total pipeline duration spent waiting
+	Reasons   []WaitReason
+}
+
+// WaitReason is the time spent waiting for one reason, e.g. Approval.
+type WaitReason struct {
+	Reason     string
+	DurationMs int64
+	Duration   string
+	Share      float64 // percentage of the total pipeline duration
+}
+
+// ComputeWaitTime summarizes the wait breakdown of a pipeline, largest reason first.
+func ComputeWaitTime(pipeline models.Pipeline) WaitTime {
+	total := pipeline.WaitMs + pipeline.RunMs
+	wait := WaitTime{
+		WaitMs:    pipeline.WaitMs,
+		Wait:      formatDuration(pipeline.WaitMs),
+		RunMs:     pipeline.RunMs,
+		Run:       formatDuration(pipeline.RunMs),
+		WaitShare: share(pipeline.WaitMs, total),
+	}
+	for reason, ms := range pipeline.WaitByReason {
+		wait.Reasons = append(wait.Reasons, WaitReason{Reason: reason, DurationMs: ms, Duration: formatDuration(ms), Share: share(ms, total)})
+	}
+	sort.Slice(wait.Reasons, func(i, j int) bool {
+		if wait.Reasons[i].DurationMs != wait.Reasons[j].DurationMs {
+			return wait.Reasons[i].DurationMs > wait.Reasons[j].DurationMs
+		}
+		return wait.Reasons[i].Reason < wait.Reasons[j].Reason
+	})
+	return wait
+}
@@ -5,41 +5,216 @@ package models
 
 // Pipeline represents a pipeline with its stages and steps.
 type Pipeline struct {
-	Name          string  `json:"name"`
-	Status        string  `json:"status"`
-	StartedTime   string  `json:"startedTime"`
-	Duration      string  `json:"duration"`
-	StageCount    int     `json:"stageCount"`
-	StepCount     int     `json:"stepCount"`
-	Message       string  `json:"message"`
-	Stages        []Stage `json:"stages"`
-	ExecutionLink string  `json:"executionLink"`
-	ExecutionId   string  `json:"executionId"`
+	Name           string           `json:"name"`
+	Status         string           `json:"status"`
+	StartedTime    string           `json:"startedTime"`
+	Duration       string           `json:"duration"`
+	StageCount     int              `json:"stageCount"`
+	StepCount      int              `json:"stepCount"`
+	Message        string           `json:"message"`
+	Stages         []Stage          `json:"stages"`
+	ExecutionLink  string           `json:"executionLink"`
+	ExecutionId    string           `json:"executionId"`
+	StartMs        int64            `json:"startMs"`
+	EndMs          int64            `json:"endMs"`
+	Graph          ExecutionGraph   `json:"graph"`
+	Comparison     *Comparison      `json:"comparison,omitempty"`
+	CommitSha      string           `json:"commitSha"`
+	Commits        []Commit         `json:"commits,omitempty"`      // commits of the CI commit info, latest first
+	Environments   []string         `json:"environments,omitempty"` // environments deployed to by CD stages
+	TriggerType    string           `json:"triggerType"`            // e.g. MANUAL, WEBHOOK, SCHEDULER_CRON
+	TriggeredBy    string           `json:"triggeredBy"`            // email or identifier of the user or trigger
+	FlakySteps     []FlakyStep      `json:"flakySteps,omitempty"`
+	Trend          *Trend           `json:"trend,omitempty"`
+	WaitMs         int64            `json:"waitMs"`
+	RunMs          int64            `json:"runMs"`
+	WaitByReason   map[string]int64 `json:"waitByReason,omitempty"`
+	BudgetBreaches []BudgetBreach   `json:"budgetBreaches,omitempty"`
 }
 
 // Stage represents a stage in a pipeline with its steps.
 type Stage struct {
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	Module   string `json:"module"`
-	StartTs  string `json:"startTs"`
-	EndTs    string `json:"endTs"`
-	Duration string `json:"duration"`
-	Steps    []Step `json:"steps"`
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Status   string         `json:"status"`
+	Module   string         `json:"module"`
+	StartTs  string         `json:"startTs"`
+	EndTs    string         `json:"endTs"`
+	Duration string         `json:"duration"`
+	StartMs  int64          `json:"startMs"`
+	EndMs    int64          `json:"endMs"`
+	Steps    []Step         `json:"steps"`
+	Graph    ExecutionGraph `json:"graph"`
+	WaitMs   int64          `json:"waitMs"`
+	RunMs    int64          `json:"runMs"`
 }
 
 // Step represents a step in a stage.
 type Step struct {
+	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Status      string `json:"status"`
 	Message     string `json:"message"`
 	StartTs     string `json:"startTs"`
 	EndTs       string `json:"endTs"`
 	Duration    string `json:"duration"`
+	StartMs     int64  `json:"startMs"`
+	EndMs       int64  `json:"endMs"`
 	FailureInfo struct {
 		Message         string   `json:"message"`
 		FailureTypeList []string `json:"failureTypeList"`
 	} `json:"failureInfo"`
+	StepType   string         `json:"stepType"`
+	Interrupts []Interrupt    `json:"interrupts,omitempty"`
+	Waits      []WaitInterval `json:"waits,omitempty"`
+	WaitMs     int64          `json:"waitMs"`
+	RunMs      int64          `json:"runMs"`
+}
+
+// ExecutionGraph keeps the edges between the nodes of an execution, stages
+// at the pipeline level and steps inside a stage.
+type ExecutionGraph struct {
+	RootID string               `json:"rootId"`
+	Nodes  map[string]GraphNode `json:"nodes"`
+}
+
+// GraphNode is a node of an ExecutionGraph. Children run in parallel inside
+// the node, Next runs after it.
+type GraphNode struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Status   string   `json:"status"`
+	StartMs  int64    `json:"startMs"`
+	EndMs    int64    `json:"endMs"`
+	Children []string `json:"children"`
+	Next     []string `json:"next"`
+	Hidden   bool     `json:"hidden,omitempty"` // wrapper or setup node without a step of its own, followed but never on the path
+}
+
+// Comparison holds the differences between an execution and a previous one
+// of the same pipeline, branch and repository.
+type Comparison struct {
+	PreviousExecutionId   string       `json:"previousExecutionId"`
+	PreviousStatus        string       `json:"previousStatus"`
+	PreviousExecutionLink string       `json:"previousExecutionLink"`
+	PreviousStartMs       int64        `json:"previousStartMs"`
+	DurationDeltaMs       int64        `json:"durationDeltaMs"`
+	Slower                []StepChange `json:"slower"`
+	Faster                []StepChange `json:"faster"`
+	NewlyFailing          []StepChange `json:"newlyFailing"`
+	NewlyFixed            []StepChange `json:"newlyFixed"`
+	Added                 []StepChange `json:"added"`
+	Removed               []StepChange `json:"removed"`
+}
+
+// StepChange describes how a stage (Step is empty) or a step changed between two executions.
+type StepChange struct {
+	Stage          string  `json:"stage"`
+	Step           string  `json:"step"`
+	PreviousStatus string  `json:"previousStatus"`
+	CurrentStatus  string  `json:"currentStatus"`
+	PreviousMs     int64   `json:"previousMs"`
+	CurrentMs      int64   `json:"currentMs"`
+	DeltaMs        int64   `json:"deltaMs"`
+	DeltaPercent   float64 `json:"deltaPercent"`
+}
+
+// BudgetBreach is a pipeline, stage or step that ran longer than its duration budget.
+// Stage and Step are empty for a pipeline budget, Step for a stage budget.
+type BudgetBreach struct {
+	Stage       string  `json:"stage"`
+	Step        string  `json:"step"`
+	Rule        string  `json:"rule"` // the matching budget, e.g. "Build/*/unit <= 5m0s"
+	BudgetMs    int64   `json:"budgetMs"`
+	ActualMs    int64   `json:"actualMs"`
+	OverMs      int64   `json:"overMs"`
+	OverPercent float64 `json:"overPercent"`
+	ExcludeWait bool    `json:"excludeWait"` // ActualMs is the running time only
+}
+
+// Interrupt is an entry of the interrupt history of a node, e.g. a pause or a manual intervention.
+type Interrupt struct {
+	InterruptId   string `json:"interruptId"`
+	InterruptType string `json:"interruptType"`
+	TookEffectAt  int64  `json:"tookEffectAt"`
+}
+
+// WaitInterval is a period a step spent waiting instead of running.
+type WaitInterval struct {
+	Reason  string `json:"reason"` // e.g. Approval, Resource, Barrier, Paused
+	StartMs int64  `json:"startMs"`
+	EndMs   int64  `json:"endMs"`
+}
+
+// Trend holds the durations and outcomes of recent executions, oldest first.
+type Trend struct {
+	Executions  []TrendPoint `json:"executions"`
+	Stages      []string     `json:"stages"` // stage names in execution order
+	SuccessRate float64      `json:"successRate"`
+}
+
+// TrendPoint is one execution of a Trend.
+type TrendPoint struct {
+	ExecutionId      string           `json:"executionId"`
+	Status           string           `json:"status"`
+	StartMs          int64            `json:"startMs"`
+	DurationMs       int64            `json:"durationMs"`
+	StageDurationsMs map[string]int64 `json:"stageDurationsMs"`
+}
+
+// Deployment is one execution of a deployment pipeline used for DORA metrics.
+type Deployment struct {
+	ExecutionId string   `json:"executionId"`
+	Status      string   `json:"status"`
+	StartMs     int64    `json:"startMs"`
+	EndMs       int64    `json:"endMs"`
+	DurationMs  int64    `json:"durationMs"`
+	Commits     []Commit `json:"commits"`
+}
+
+// Commit is a commit shipped by a deployment.
+type Commit struct {
+	Sha         string `json:"sha"`
+	Message     string `json:"message"`
+	TimestampMs int64  `json:"timestampMs"`
+}
+
+// DoraReport holds the four DORA metrics of a pipeline over a time window.
+type DoraReport struct {
+	PipelineId            string       `json:"pipelineId"`
+	PipelineName          string       `json:"pipelineName"`
+	WindowStartMs         int64        `json:"windowStartMs"`
+	WindowEndMs           int64        `json:"windowEndMs"`
+	WindowDays            int          `json:"windowDays"`
+	DeploymentCount       int          `json:"deploymentCount"` // finished deployments, aborted ones excluded
+	SuccessfulDeployments int          `json:"successfulDeployments"`
+	FailedDeployments     int          `json:"failedDeployments"`
+	DeploymentsPerDay     float64      `json:"deploymentsPerDay"`
+	DeploymentFrequency   string       `json:"deploymentFrequency"` // DORA performance level: Elite, High, Medium or Low
+	LeadTimeMedianMs      int64        `json:"leadTimeMedianMs"`
+	LeadTimeSamples       int          `json:"leadTimeSamples"` // commits with a timestamp shipped by a successful deployment
+	LeadTime              string       `json:"leadTime"`
+	ChangeFailureRate     float64      `json:"changeFailureRate"` // 0 to 100
+	ChangeFailure         string       `json:"changeFailure"`
+	RestoreMedianMs       int64        `json:"restoreMedianMs"`
+	Incidents             int          `json:"incidents"`           // runs of failed deployments
+	UnresolvedIncidents   int          `json:"unresolvedIncidents"` // incidents not followed by a successful deployment yet
+	TimeToRestore         string       `json:"timeToRestore"`
+	Deployments           []Deployment `json:"deployments"` // oldest first
+}
+
+// FlakyStep is a step that alternated between success and failure across
+// recent executions without a code change in between.
+type FlakyStep struct {
+	Stage      string   `json:"stage"`
+	Step       string   `json:"step"`
+	Runs       int      `json:"runs"`
+	Failures   int      `json:"failures"`
+	Flips      int      `json:"flips"`
+	Score      float64  `json:"score"` // flips without code change per pair of consecutive runs, 0 to 100
+	LastStatus string   `json:"lastStatus"`
+	Executions []string `json:"executions"` // executions where the step flipped
 }
 
 // steps parsing
@@ -47,11 +222,19 @@ type PayloadSteps struct {
 	Status string `json:"status"`
 	Data   struct {
 		ExecutionGraph struct {
-			NodeMap map[string]Node `json:"nodeMap"`
+			RootNodeId           string              `json:"rootNodeId"`
+			NodeMap              map[string]Node     `json:"nodeMap"`
+			NodeAdjacencyListMap map[string]NodeEdge `json:"nodeAdjacencyListMap"`
 		} `json:"executionGraph"`
 	} `json:"data"`
 }
 
+// NodeEdge lists the children and next nodes of a node in the execution graph.
+type NodeEdge struct {
+	Children []string `json:"children"`
+	NextIds  []string `json:"nextIds"`
+}
+
 type Node struct {
 	Name        string `json:"name"`
 	Identifier  string `json:"identifier"`
@@ -63,6 +246,7 @@ type Node struct {
 		Message         string   `json:"message"`
 		FailureTypeList []string `json:"failureTypeList"`
 	} `json:"failureInfo"`
+	InterruptHistories []Interrupt `json:"interruptHistories"`
 }
 
 // PLUGIN CORE
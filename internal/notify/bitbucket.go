@@ -0,0 +1,160 @@
+// notify/bitbucket.go
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBitbucketAPI is the API of Bitbucket Cloud. Base URLs ending in /2.0
+// are taken as the Cloud API, any other as a Bitbucket Data Center (Server)
+// instance, e.g. https://bitbucket.example.com.
+const DefaultBitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// bitbucketKeyLimit is the maximum length of a build status key in Bitbucket Cloud.
+const bitbucketKeyLimit = 40
+
+// Bitbucket reports executions to a Bitbucket Cloud or Data Center repository.
+type Bitbucket struct {
+	BaseURL string
+	// Username switches to basic authentication with Token as app password.
+	// Without it Token is sent as a bearer token (access token or HTTP access token).
+	Username string
+	Token    string
+	Project  string // workspace in Bitbucket Cloud, project key in Data Center
+	Repo     string // repository slug
+	Sender   *Sender
+}
+
+// Cloud reports whether the base URL points to the Bitbucket Cloud API.
+func (b Bitbucket) Cloud() bool {
+	return strings.HasSuffix(b.base(), "/2.0")
+}
+
+// SetStatus sets the build status of sha.
+func (b Bitbucket) SetStatus(sha string, status CommitStatus) error {
+	state := map[string]string{
+		OutcomeSuccess: "SUCCESSFUL",
+		OutcomeFailure: "FAILED",
+		OutcomePending: "INPROGRESS",
+		OutcomeError:   "FAILED",
+	}[status.Outcome]
+	if b.Cloud() && status.Outcome == OutcomeError {
+		state = "STOPPED"
+	}
+	payload := map[string]string{
+		"key":         description(status.Context, bitbucketKeyLimit),
+		"state":       state,
+		"name":        status.Name,
+		"url":         status.URL,
+		"description": description(status.Description, 255),
+	}
+
+	endpoint := b.base() + "/rest/build-status/1.0/commits/" + url.PathEscape(sha)
+	if b.Cloud() {
+		endpoint = b.repoURL("commit", url.PathEscape(sha), "statuses", "build")
+	}
+	return b.Sender.JSON("bitbucket status", http.MethodPost, endpoint, b.header(), payload, nil)
+}
+
+// UpsertComment updates the pull request comment carrying the marker of comment, or creates it.
+func (b Bitbucket) UpsertComment(pullRequest int, comment Comment) error {
+	if b.Cloud() {
+		return b.upsertCloudComment(pullRequest, comment)
+	}
+	return b.upsertDataCenterComment(pullRequest, comment)
+}
+
+type bitbucketCloudComments struct {
+	Values []struct {
+		ID      int64 `json:"id"`
+		Content struct {
+			Raw string `json:"raw"`
+		} `json:"content"`
+		Deleted bool `json:"deleted"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+func (b Bitbucket) upsertCloudComment(pullRequest int, comment Comment) error {
+	comments := b.repoURL("pullrequests", fmt.Sprint(pullRequest), "comments")
+	body := map[string]interface{}{"content": map[string]string{"raw": comment.Text(0)}}
+
+	next := comments + "?pagelen=100"
+	for page := 0; page < 10 && next != ""; page++ {
+		var list bitbucketCloudComments
+		if err := b.Sender.JSON("bitbucket comments", http.MethodGet, next, b.header(), nil, &list); err != nil {
+			return err
+		}
+		for _, existing := range list.Values {
+			if !existing.Deleted && strings.HasPrefix(existing.Content.Raw, comment.Marker) {
+				return b.Sender.JSON("bitbucket comment update", http.MethodPut, comments+"/"+fmt.Sprint(existing.ID), b.header(), body, nil)
+			}
+		}
+		next = list.Next
+	}
+	return b.Sender.JSON("bitbucket comment", http.MethodPost, comments, b.header(), body, nil)
+}
+
+type bitbucketActivities struct {
+	Values []struct {
+		Action  string `json:"action"`
+		Comment struct {
+			ID      int64  `json:"id"`
+			Text    string `json:"text"`
+			Version int    `json:"version"`
+		} `json:"comment"`
+	} `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+func (b Bitbucket) upsertDataCenterComment(pullRequest int, comment Comment) error {
+	pull := b.base() + "/rest/api/1.0/projects/" + url.PathEscape(b.Project) + "/repos/" + url.PathEscape(b.Repo) + "/pull-requests/" + fmt.Sprint(pullRequest)
+	text := comment.Text(0)
+
+	start := 0
+	for page := 0; page < 10; page++ {
+		var activities bitbucketActivities
+		if err := b.Sender.JSON("bitbucket comments", http.MethodGet, fmt.Sprintf("%s/activities?limit=100&start=%d", pull, start), b.header(), nil, &activities); err != nil {
+			return err
+		}
+		for _, activity := range activities.Values {
+			if activity.Action == "COMMENTED" && strings.HasPrefix(activity.Comment.Text, comment.Marker) {
+				body := map[string]interface{}{"text": text, "version": activity.Comment.Version}
+				return b.Sender.JSON("bitbucket comment update", http.MethodPut, pull+"/comments/"+fmt.Sprint(activity.Comment.ID), b.header(), body, nil)
+			}
+		}
+		if activities.IsLastPage {
+			break
+		}
+		start = activities.NextPageStart
+	}
+	return b.Sender.JSON("bitbucket comment", http.MethodPost, pull+"/comments", b.header(), map[string]string{"text": text}, nil)
+}
+
+func (b Bitbucket) base() string {
+	base := strings.TrimSuffix(b.BaseURL, "/")
+	if base == "" {
+		return DefaultBitbucketAPI
+	}
+	return base
+}
+
+// repoURL builds a Bitbucket Cloud repository endpoint.
+func (b Bitbucket) repoURL(parts ...string) string {
+	return b.base() + "/repositories/" + url.PathEscape(b.Project) + "/" + url.PathEscape(b.Repo) + "/" + strings.Join(parts, "/")
+}
+
+func (b Bitbucket) header() http.Header {
+	header := http.Header{}
+	if b.Username != "" {
+		request := http.Request{Header: header}
+		request.SetBasicAuth(b.Username, b.Token)
+	} else {
+		header.Set("Authorization", "Bearer "+b.Token)
+	}
+	return header
+}
@@ -0,0 +1,78 @@
+// notify/github.go
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultGitHubAPI is the API of github.com. GitHub Enterprise Server uses https://<host>/api/v3.
+const DefaultGitHubAPI = "https://api.github.com"
+
+// githubCommentLimit is the maximum size of an issue comment body.
+const githubCommentLimit = 65536
+
+// GitHub reports executions to a GitHub repository.
+type GitHub struct {
+	BaseURL string
+	Token   string
+	Repo    string // owner/name
+	Sender  *Sender
+}
+
+type githubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// SetStatus sets the commit status of sha. The outcomes match the GitHub states.
+func (g GitHub) SetStatus(sha string, status CommitStatus) error {
+	payload := map[string]string{
+		"state":       status.Outcome,
+		"context":     status.Context,
+		"description": description(status.Description, 140),
+	}
+	if status.URL != "" {
+		payload["target_url"] = status.URL
+	}
+	return g.Sender.JSON("github status", http.MethodPost, g.url("statuses", url.PathEscape(sha)), g.header(), payload, nil)
+}
+
+// UpsertComment updates the pull request comment carrying the marker of comment, or creates it.
+func (g GitHub) UpsertComment(pullRequest int, comment Comment) error {
+	body := map[string]string{"body": comment.Text(githubCommentLimit)}
+	for page := 1; page <= 10; page++ {
+		var comments []githubComment
+		endpoint := g.url("issues", fmt.Sprint(pullRequest), "comments") + fmt.Sprintf("?per_page=100&page=%d", page)
+		if err := g.Sender.JSON("github comments", http.MethodGet, endpoint, g.header(), nil, &comments); err != nil {
+			return err
+		}
+		for _, existing := range comments {
+			if strings.HasPrefix(existing.Body, comment.Marker) {
+				return g.Sender.JSON("github comment update", http.MethodPatch, g.url("issues", "comments", fmt.Sprint(existing.ID)), g.header(), body, nil)
+			}
+		}
+		if len(comments) < 100 {
+			break
+		}
+	}
+	return g.Sender.JSON("github comment", http.MethodPost, g.url("issues", fmt.Sprint(pullRequest), "comments"), g.header(), body, nil)
+}
+
+func (g GitHub) url(parts ...string) string {
+	base := strings.TrimSuffix(g.BaseURL, "/")
+	if base == "" {
+		base = DefaultGitHubAPI
+	}
+	return base + "/repos/" + g.Repo + "/" + strings.Join(parts, "/")
+}
+
+func (g GitHub) header() http.Header {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+g.Token)
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return header
+}
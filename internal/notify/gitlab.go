@@ -0,0 +1,81 @@
+// notify/gitlab.go
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultGitLabURL is gitlab.com. Self-managed instances use their own base URL.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// GitLab reports executions to a GitLab project.
+type GitLab struct {
+	BaseURL string
+	Token   string
+	Project string // numeric ID or path, e.g. "acme/payments"
+	Sender  *Sender
+}
+
+type gitlabNote struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+}
+
+// SetStatus sets an external commit status on sha.
+func (g GitLab) SetStatus(sha string, status CommitStatus) error {
+	state := map[string]string{
+		OutcomeSuccess: "success",
+		OutcomeFailure: "failed",
+		OutcomePending: "running",
+		OutcomeError:   "canceled",
+	}[status.Outcome]
+	payload := map[string]string{
+		"state":       state,
+		"name":        status.Context,
+		"description": description(status.Description, 255),
+	}
+	if status.URL != "" {
+		payload["target_url"] = status.URL
+	}
+	return g.Sender.JSON("gitlab status", http.MethodPost, g.url("statuses", url.PathEscape(sha)), g.header(), payload, nil)
+}
+
+// UpsertComment updates the merge request note carrying the marker of comment, or creates it.
+func (g GitLab) UpsertComment(mergeRequest int, comment Comment) error {
+	notes := g.url("merge_requests", fmt.Sprint(mergeRequest), "notes")
+	body := map[string]string{"body": comment.Text(0)}
+	for page := 1; page <= 10; page++ {
+		var list []gitlabNote
+		if err := g.Sender.JSON("gitlab notes", http.MethodGet, fmt.Sprintf("%s?per_page=100&page=%d", notes, page), g.header(), nil, &list); err != nil {
+			return err
+		}
+		for _, note := range list {
+			if !note.System && strings.HasPrefix(note.Body, comment.Marker) {
+				return g.Sender.JSON("gitlab note update", http.MethodPut, notes+"/"+fmt.Sprint(note.ID), g.header(), body, nil)
+			}
+		}
+		if len(list) < 100 {
+			break
+		}
+	}
+	return g.Sender.JSON("gitlab note", http.MethodPost, notes, g.header(), body, nil)
+}
+
+// url builds a project endpoint; a project path is sent URL-encoded as GitLab expects.
+func (g GitLab) url(parts ...string) string {
+	base := strings.TrimSuffix(g.BaseURL, "/")
+	if base == "" {
+		base = DefaultGitLabURL
+	}
+	return base + "/api/v4/projects/" + url.PathEscape(g.Project) + "/" + strings.Join(parts, "/")
+}
+
+func (g GitLab) header() http.Header {
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", g.Token)
+	return header
+}
@@ -0,0 +1,70 @@
+// notify/jira.go
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNoTransition is returned when an issue has no transition with the requested name.
+var ErrNoTransition = errors.New("no such transition")
+
+// Jira comments on and transitions Jira Cloud or Data Center issues through the REST API v2.
+type Jira struct {
+	BaseURL string
+	// Username switches to basic authentication with Token as API token (Jira Cloud).
+	// Without it Token is sent as a bearer personal access token (Data Center).
+	Username string
+	Token    string
+	Sender   *Sender
+}
+
+type jiraTransitions struct {
+	Transitions []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		To   struct {
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+// Comment adds a comment in Jira wiki markup to the issue.
+func (j Jira) Comment(issue string, text string) error {
+	return j.Sender.JSON("jira comment on "+issue, http.MethodPost, j.url(issue, "comment"), j.header(), map[string]string{"body": text}, nil)
+}
+
+// Transition moves the issue through the transition named name, or leading to
+// the status named name. It returns ErrNoTransition when the issue has none,
+// e.g. because it already is in that status.
+func (j Jira) Transition(issue string, name string) error {
+	var available jiraTransitions
+	if err := j.Sender.JSON("jira transitions of "+issue, http.MethodGet, j.url(issue, "transitions"), j.header(), nil, &available); err != nil {
+		return err
+	}
+	for _, transition := range available.Transitions {
+		if strings.EqualFold(transition.Name, name) || strings.EqualFold(transition.To.Name, name) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			return j.Sender.JSON("jira transition of "+issue, http.MethodPost, j.url(issue, "transitions"), j.header(), payload, nil)
+		}
+	}
+	return fmt.Errorf("%s %q: %w", issue, name, ErrNoTransition)
+}
+
+func (j Jira) url(issue string, resource string) string {
+	return strings.TrimSuffix(j.BaseURL, "/") + "/rest/api/2/issue/" + url.PathEscape(issue) + "/" + resource
+}
+
+func (j Jira) header() http.Header {
+	header := http.Header{}
+	if j.Username != "" {
+		request := http.Request{Header: header}
+		request.SetBasicAuth(j.Username, j.Token)
+	} else {
+		header.Set("Authorization", "Bearer "+j.Token)
+	}
+	return header
+}
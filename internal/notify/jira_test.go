@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// jiraRequest is a request received by the Jira stand-in.
+type jiraRequest struct {
+	Method, Path, Authorization, Body string
+}
+
+// jiraServer is a Jira stand-in under /jira that answers the transitions of
+// PAY-12 with transitions, accepts every POST and records the requests.
+func jiraServer(t *testing.T, transitions string) (*httptest.Server, *[]jiraRequest) {
+	t.Helper()
+	var requests []jiraRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, jiraRequest{r.Method, r.URL.Path, r.Header.Get("Authorization"), string(body)})
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/jira/rest/api/2/issue/PAY-12/transitions":
+			io.WriteString(w, transitions)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+const deployTransition = `{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}},{"id":"31","name":"Deploy","to":{"name":"Done"}}]}`
+
+func TestJiraComment(t *testing.T) {
+	server, requests := jiraServer(t, deployTransition)
+	jira := Jira{BaseURL: server.URL + "/jira/", Username: "ci@example.com", Token: "api-token", Sender: testSender(0, io.Discard)}
+
+	if err := jira.Comment("PAY-12", "Harness pipeline *deploy*: *Success*"); err != nil {
+		t.Fatalf("Comment: %v", err)
+	}
+	request := (*requests)[0]
+	if request.Method != http.MethodPost || request.Path != "/jira/rest/api/2/issue/PAY-12/comment" {
+		t.Errorf("request = %s %s", request.Method, request.Path)
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(request.Body), &body); err != nil || body["body"] != "Harness pipeline *deploy*: *Success*" {
+		t.Errorf("body = %s", request.Body)
+	}
+}
+
+func TestJiraAuthentication(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+	}{
+		{name: "basic with an API token", username: "ci@example.com", want: "Basic Y2lAZXhhbXBsZS5jb206c2VjcmV0"},
+		{name: "bearer personal access token", want: "Bearer secret"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, requests := jiraServer(t, deployTransition)
+			jira := Jira{BaseURL: server.URL + "/jira", Username: test.username, Token: "secret", Sender: testSender(0, io.Discard)}
+			if err := jira.Comment("PAY-12", "done"); err != nil {
+				t.Fatalf("Comment: %v", err)
+			}
+			if got := (*requests)[0].Authorization; got != test.want {
+				t.Errorf("Authorization = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJiraTransition(t *testing.T) {
+	for _, name := range []string{"Deploy", "deploy", "Done", "DONE"} {
+		t.Run(name, func(t *testing.T) {
+			server, requests := jiraServer(t, deployTransition)
+			jira := Jira{BaseURL: server.URL + "/jira", Token: "secret", Sender: testSender(0, io.Discard)}
+
+			if err := jira.Transition("PAY-12", name); err != nil {
+				t.Fatalf("Transition: %v", err)
+			}
+			if len(*requests) != 2 {
+				t.Fatalf("requests = %+v", *requests)
+			}
+			post := (*requests)[1]
+			if post.Method != http.MethodPost || post.Path != "/jira/rest/api/2/issue/PAY-12/transitions" || post.Body != `{"transition":{"id":"31"}}` {
+				t.Errorf("transition request = %+v", post)
+			}
+		})
+	}
+}
+
+func TestJiraTransitionNotAvailable(t *testing.T) {
+	server, requests := jiraServer(t, `{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}}]}`)
+	jira := Jira{BaseURL: server.URL + "/jira", Token: "secret", Sender: testSender(0, io.Discard)}
+
+	err := jira.Transition("PAY-12", "Done")
+	if !errors.Is(err, ErrNoTransition) {
+		t.Fatalf("err = %v, want ErrNoTransition", err)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("requests = %+v, want the lookup only", *requests)
+	}
+}
+
+func TestJiraTransitionLookupFailure(t *testing.T) {
+	server, _ := jiraServer(t, deployTransition)
+	jira := Jira{BaseURL: server.URL + "/jira", Token: "secret", Sender: testSender(0, io.Discard)}
+
+	var statusErr *StatusError
+	if err := jira.Transition("OPS-1", "Done"); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("err = %v, want the 404", err)
+	}
+}
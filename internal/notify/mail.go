@@ -0,0 +1,295 @@
+// notify/mail.go
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP connection security modes.
+const (
+	SecurityStartTLS = "starttls" // plain connection upgraded with STARTTLS, usually port 587
+	SecurityTLS      = "tls"      // implicit TLS, usually port 465
+	SecurityNone     = "none"     // no encryption, for local relays and test servers
+)
+
+// SMTPServer holds the connection settings of an SMTP relay.
+type SMTPServer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Security string
+}
+
+// Mail is a multipart message with a plain-text body and an HTML version,
+// shown inline as an alternative or added as an attachment.
+type Mail struct {
+	From           string
+	To             []string
+	Subject        string
+	Text           []byte
+	HTML           []byte
+	AttachHTML     bool
+	AttachmentName string
+}
+
+// SendMail delivers message through server, retrying network errors and
+// temporary (4xx) SMTP replies like Do retries HTTP requests.
+func (s *Sender) SendMail(name string, server SMTPServer, message Mail) error {
+	from, recipients, err := message.addresses()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	content, err := message.Bytes()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if s.DryRun {
+		fmt.Fprintf(s.Out, "| [DRY RUN] %s message to %s:\n%s\n", name, strings.Join(recipients, ", "), content)
+		return nil
+	}
+
+	backoff := s.Backoff
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			fmt.Fprintf(s.Out, "| \033[33m[WARNING] - %s attempt %d failed: %v, retrying in %s\033[0m\n", name, attempt, err, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		err = server.send(from, recipients, content)
+		if err == nil || !temporarySMTPError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// send makes one SMTP transaction.
+func (server SMTPServer) send(from string, recipients []string, content []byte) error {
+	address := net.JoinHostPort(server.Host, strconv.Itoa(server.Port))
+	tlsConfig := &tls.Config{ServerName: server.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	switch strings.ToLower(server.Security) {
+	case SecurityTLS:
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	case SecurityStartTLS, SecurityNone, "":
+		conn, err = dialer.Dial("tcp", address)
+	default:
+		return fmt.Errorf("unknown SMTP security %q, use starttls, tls or none", server.Security)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(2 * time.Minute))
+
+	client, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if strings.EqualFold(server.Security, SecurityStartTLS) || server.Security == "" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("the SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if server.Username != "" {
+		// PlainAuth refuses to send the password over an unencrypted connection, except to localhost
+		if err := client.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("recipient %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// temporarySMTPError reports whether a failed delivery is worth retrying:
+// network errors and 4xx replies are, 5xx replies and configuration errors are not.
+func temporarySMTPError(err error) bool {
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) {
+		return protocolErr.Code >= 400 && protocolErr.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// addresses returns the envelope sender and recipients.
+func (m Mail) addresses() (string, []string, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	var recipients []string
+	for _, to := range m.To {
+		list, err := mail.ParseAddressList(to)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		for _, address := range list {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return "", nil, errors.New("no recipients")
+	}
+	return from.Address, recipients, nil
+}
+
+// Bytes renders the message as RFC 5322 with MIME parts. The text part comes
+// first, so clients that cannot display HTML show it.
+func (m Mail) Bytes() ([]byte, error) {
+	var message bytes.Buffer
+	header := func(key string, value string) {
+		fmt.Fprintf(&message, "%s: %s\r\n", key, value)
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	var to []string
+	for _, recipient := range m.To {
+		list, err := mail.ParseAddressList(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+		for _, address := range list {
+			to = append(to, address.String())
+		}
+	}
+	subject := strings.Join(strings.Fields(m.Subject), " ")
+
+	header("From", from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+
+	writer := multipart.NewWriter(&message)
+	if m.AttachHTML && len(m.HTML) > 0 {
+		header("Content-Type", "multipart/mixed; boundary="+writer.Boundary())
+		message.WriteString("\r\n")
+		if err := writeQuotedPrintable(writer, "text/plain; charset=utf-8", m.Text); err != nil {
+			return nil, err
+		}
+		name := m.AttachmentName
+		if name == "" {
+			name = "report.html"
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType("text/html", map[string]string{"charset": "utf-8", "name": name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, m.HTML); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return message.Bytes(), nil
+	}
+
+	header("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+	message.WriteString("\r\n")
+	if err := writeQuotedPrintable(writer, "text/plain; charset=utf-8", m.Text); err != nil {
+		return nil, err
+	}
+	if len(m.HTML) > 0 {
+		if err := writeQuotedPrintable(writer, "text/html; charset=utf-8", m.HTML); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
+
+func writeQuotedPrintable(writer *multipart.Writer, contentType string, content []byte) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write(content); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeBase64 writes content base64 encoded in lines of 76 characters.
+func writeBase64(part io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(part, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := fmt.Fprintf(part, "%s\r\n", encoded)
+	return err
+}
+
+func messageID(from string) string {
+	random := make([]byte, 12)
+	rand.Read(random)
+	domain := "localhost"
+	if _, host, ok := strings.Cut(from, "@"); ok && host != "" {
+		domain = host
+	}
+	return fmt.Sprintf("<%s.%d@%s>", hex.EncodeToString(random), time.Now().UnixNano(), domain)
+}
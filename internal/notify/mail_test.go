@@ -0,0 +1,278 @@
+package notify
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// normalizeMail replaces the parts of a message that change on every run.
+func normalizeMail(t *testing.T, message []byte) string {
+	t.Helper()
+	text := string(message)
+	boundary := regexp.MustCompile(`boundary=(\S+)`).FindStringSubmatch(text)
+	if boundary == nil {
+		t.Fatalf("no boundary in %q", text)
+	}
+	text = strings.ReplaceAll(text, boundary[1], "BOUNDARY")
+	text = regexp.MustCompile(`(?m)^Date: .*\r$`).ReplaceAllString(text, "Date: DATE\r")
+	text = regexp.MustCompile(`(?m)^Message-ID: <[0-9a-f]+\.[0-9]+@example\.com>\r$`).ReplaceAllString(text, "Message-ID: ID\r")
+	return text
+}
+
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n got: %q\nwant: %q", name, got, want)
+	}
+}
+
+func TestMailBytesAlternative(t *testing.T) {
+	message := Mail{
+		From:    "CI <ci@example.com>",
+		To:      []string{"dev@example.com, Ops Team <ops@example.com>"},
+		Subject: "[Failed] Déploiement\r\nBcc: attacker@example.com",
+		Text:    []byte("Pipeline deploy: Failed\n"),
+		HTML:    []byte(`<p style="color: red">Pipeline deploy: <b>Failed</b> – see the execution</p>`),
+	}
+	content, err := message.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := normalizeMail(t, content)
+	if strings.Contains(got, "\r\nBcc:") {
+		t.Errorf("the subject injected a header:\n%s", got)
+	}
+	assertGolden(t, "alternative.eml", got)
+}
+
+func TestMailBytesAttachment(t *testing.T) {
+	message := Mail{
+		From:           "ci@example.com",
+		To:             []string{"dev@example.com"},
+		Subject:        "[Success] build",
+		Text:           []byte("Pipeline build: Success\n"),
+		HTML:           []byte("<html>" + strings.Repeat("<p>report</p>", 20) + "</html>"),
+		AttachHTML:     true,
+		AttachmentName: "build-abc123.html",
+	}
+	content, err := message.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := normalizeMail(t, content)
+	assertGolden(t, "attachment.eml", got)
+
+	_, attachment, _ := strings.Cut(got, "name=build-abc123.html\r\n\r\n")
+	attachment, _, _ = strings.Cut(attachment, "\r\n\r\n--BOUNDARY--")
+	lines := strings.Split(attachment, "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("attachment is not wrapped: %q", attachment)
+	}
+	for i, line := range lines {
+		if len(line) > 76 || (i < len(lines)-1 && len(line) != 76) {
+			t.Errorf("base64 line %d has %d characters", i, len(line))
+		}
+	}
+}
+
+func TestMailBytesInvalidAddresses(t *testing.T) {
+	if _, err := (Mail{From: "not an address", To: []string{"dev@example.com"}}).Bytes(); err == nil {
+		t.Error("expected an error for the sender")
+	}
+	if _, err := (Mail{From: "ci@example.com", To: []string{"dev@"}}).Bytes(); err == nil {
+		t.Error("expected an error for the recipient")
+	}
+}
+
+// fakeSMTP is a minimal SMTP server without STARTTLS. replies maps a command
+// (MAIL, RCPT or DATA) to the reply of each connection in turn; missing
+// entries accept the command.
+type fakeSMTP struct {
+	listener net.Listener
+	replies  map[string][]string
+
+	mu          sync.Mutex
+	connections int
+	recipients  []string
+	data        []string
+}
+
+func newFakeSMTP(t *testing.T, replies map[string][]string) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTP{listener: listener, replies: replies}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server
+}
+
+func (f *fakeSMTP) server() SMTPServer {
+	address := f.listener.Addr().(*net.TCPAddr)
+	return SMTPServer{Host: "127.0.0.1", Port: address.Port, Security: SecurityNone}
+}
+
+func (f *fakeSMTP) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		connection := f.connections
+		f.connections++
+		f.mu.Unlock()
+		f.handle(conn, connection)
+	}
+}
+
+// received returns the number of connections, the RCPT commands and the messages received so far.
+func (f *fakeSMTP) received() (int, []string, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections, f.recipients, f.data
+}
+
+func (f *fakeSMTP) reply(command string, connection int, accepted string) string {
+	if replies := f.replies[command]; connection < len(replies) && replies[connection] != "" {
+		return replies[connection]
+	}
+	return accepted
+}
+
+func (f *fakeSMTP) handle(conn net.Conn, connection int) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	write := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	write("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch command {
+		case "EHLO", "HELO":
+			write("250 localhost")
+		case "MAIL":
+			write(f.reply("MAIL", connection, "250 OK"))
+		case "RCPT":
+			f.mu.Lock()
+			f.recipients = append(f.recipients, strings.TrimSpace(line))
+			f.mu.Unlock()
+			write(f.reply("RCPT", connection, "250 OK"))
+		case "DATA":
+			write("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			f.mu.Lock()
+			f.data = append(f.data, data.String())
+			f.mu.Unlock()
+			write(f.reply("DATA", connection, "250 queued"))
+		case "QUIT":
+			write("221 bye")
+			return
+		default:
+			write("502 not implemented")
+		}
+	}
+}
+
+func mailSender(retries int) *Sender {
+	return &Sender{Retries: retries, Backoff: time.Millisecond, Out: io.Discard}
+}
+
+var testMail = Mail{From: "ci@example.com", To: []string{"dev@example.com", "ops@example.com"}, Subject: "[Success] build", Text: []byte("ok\n")}
+
+func TestSendMail(t *testing.T) {
+	server := newFakeSMTP(t, nil)
+
+	if err := mailSender(0).SendMail("email", server.server(), testMail); err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	_, recipients, data := server.received()
+	if len(recipients) != 2 || !strings.Contains(recipients[1], "<ops@example.com>") {
+		t.Errorf("recipients = %q", recipients)
+	}
+	if len(data) != 1 || !strings.Contains(data[0], "Subject: [Success] build\r\n") {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestSendMailRetriesTemporaryReplies(t *testing.T) {
+	server := newFakeSMTP(t, map[string][]string{
+		"MAIL": {"421 service not available"},
+		"DATA": {"", "451 local error"},
+	})
+
+	if err := mailSender(3).SendMail("email", server.server(), testMail); err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	connections, _, data := server.received()
+	if connections != 3 {
+		t.Errorf("connections = %d, want 3", connections)
+	}
+	if len(data) != 2 {
+		t.Errorf("messages received = %d, want the rejected one and the delivered one", len(data))
+	}
+}
+
+func TestSendMailDoesNotRetryPermanentReplies(t *testing.T) {
+	server := newFakeSMTP(t, map[string][]string{"RCPT": {"550 no such user"}})
+
+	err := mailSender(3).SendMail("email", server.server(), testMail)
+	if err == nil || !strings.Contains(err.Error(), "550") || !strings.Contains(err.Error(), "no such user") {
+		t.Fatalf("err = %v, want the 550 reply", err)
+	}
+	if connections, _, _ := server.received(); connections != 1 {
+		t.Errorf("connections = %d, want 1", connections)
+	}
+}
+
+func TestSendMailDryRun(t *testing.T) {
+	var out strings.Builder
+	sender := mailSender(0)
+	sender.DryRun, sender.Out = true, &out
+
+	unreachable := SMTPServer{Host: "127.0.0.1", Port: 1, Security: SecurityNone}
+	if err := sender.SendMail("email", unreachable, testMail); err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	if !strings.Contains(out.String(), "[DRY RUN] email message to dev@example.com, ops@example.com") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+}
@@ -0,0 +1,92 @@
+// notify/scm.go
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Outcomes of an execution as reported to source control hosts.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomePending = "pending"
+	OutcomeError   = "error" // aborted or otherwise stopped without a verdict
+)
+
+// Outcome maps a Harness status to the outcome reported as commit status.
+func Outcome(status string) string {
+	switch status {
+	case "Success", "IgnoreFailed":
+		return OutcomeSuccess
+	case "Failed", "Errored", "Expired", "ApprovalRejected":
+		return OutcomeFailure
+	case "Aborted", "AbortedByFreeze", "Discontinuing":
+		return OutcomeError
+	}
+	return OutcomePending
+}
+
+// CommitStatus is the build status of an execution attached to a commit.
+type CommitStatus struct {
+	Outcome     string
+	Context     string // identifies the status among the others of the commit, e.g. "harness/build"
+	Name        string // human readable name, shown by hosts that support it
+	Description string
+	URL         string
+}
+
+// Comment is a pull request comment updated in place by later executions.
+// Marker is a hidden HTML comment identifying the comment among the others.
+type Comment struct {
+	Marker string
+	Body   string
+}
+
+// Text returns the comment body starting with its marker.
+func (c Comment) Text(limit int) string {
+	text := c.Marker + "\n" + c.Body
+	if limit > 0 && len(text) > limit {
+		const notice = "\n\n_The summary was truncated._"
+		text = strings.ToValidUTF8(text[:limit-len(notice)], "") + notice
+	}
+	return text
+}
+
+// JSON sends payload as JSON and decodes the answer into result, either of which may be nil.
+func (s *Sender) JSON(name string, method string, url string, header http.Header, payload interface{}, result interface{}) error {
+	request := Request{Method: method, URL: url, Header: http.Header{}}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if request.Header.Get("Accept") == "" {
+		request.Header.Set("Accept", "application/json")
+	}
+	if payload != nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		request.Body = body
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	body, err := s.Do(name, request)
+	if err != nil || result == nil || len(body) == 0 {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("%s: error parsing response: %w", name, err)
+	}
+	return nil
+}
+
+// description shortens a status description to limit bytes.
+func description(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return strings.ToValidUTF8(text[:limit-3], "") + "..."
+}
@@ -0,0 +1,170 @@
+// notify/sender.go
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Request is one HTTP call made by a notifier.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Sender delivers notifications over HTTP, retrying transport errors, rate
+// limiting (429) and server errors (5xx) with a doubling backoff. Webhook URLs
+// usually embed a secret, so they are never printed.
+type Sender struct {
+	Client  *http.Client
+	Retries int           // attempts after the first one
+	Backoff time.Duration // wait before the first retry, doubled after each one
+	// DryRun prints the payloads instead of sending them. GET requests are still made.
+	DryRun bool
+	Out    io.Writer
+}
+
+// NewSender returns a sender with a 30 second timeout and a one second initial backoff.
+func NewSender(retries int, dryRun bool) *Sender {
+	if retries < 0 {
+		retries = 0
+	}
+	return &Sender{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		Retries: retries,
+		Backoff: time.Second,
+		DryRun:  dryRun,
+		Out:     os.Stdout,
+	}
+}
+
+// StatusError is returned when the server answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// PostJSON posts payload as JSON to url. name identifies the target in logs and errors.
+func (s *Sender) PostJSON(name string, url string, payload []byte) ([]byte, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return s.Do(name, Request{Method: http.MethodPost, URL: url, Header: header, Body: payload})
+}
+
+// Do sends request and returns the response body of the first 2xx answer.
+func (s *Sender) Do(name string, request Request) ([]byte, error) {
+	if s.DryRun && request.Method != http.MethodGet {
+		fmt.Fprintf(s.Out, "| [DRY RUN] %s %s request:\n%s\n", name, request.Method, dryRunBody(request))
+		return nil, nil
+	}
+
+	backoff := s.Backoff
+	var err error
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			fmt.Fprintf(s.Out, "| \033[33m[WARNING] - %s attempt %d failed: %v, retrying in %s\033[0m\n", name, attempt, err, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		var body []byte
+		var retryAfter time.Duration
+		body, retryAfter, err = s.send(request)
+		if err == nil {
+			return body, nil
+		}
+		if !retryable(err) {
+			break
+		}
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", name, err)
+}
+
+// send makes a single attempt. retryAfter holds the delay asked for by a 429 answer.
+func (s *Sender) send(request Request) (body []byte, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(request.Method, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		// the error would print the URL
+		return nil, 0, fmt.Errorf("invalid %s request", request.Method)
+	}
+	for key, values := range request.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, &transportError{err: err}
+	}
+	defer res.Body.Close()
+
+	body, err = io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, 0, &transportError{err: err}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, retryAfter, &StatusError{StatusCode: res.StatusCode, Body: string(truncate(body, 300))}
+	}
+	return body, 0, nil
+}
+
+// transportError hides the request URL that net/http includes in its errors.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	if urlErr, ok := e.err.(interface{ Unwrap() error }); ok && urlErr.Unwrap() != nil {
+		return "request failed: " + urlErr.Unwrap().Error()
+	}
+	return "request failed"
+}
+
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case *transportError:
+		return true
+	case *StatusError:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	}
+	return false
+}
+
+// dryRunBody pretty prints JSON payloads and summarizes the others, such as uploaded reports.
+func dryRunBody(request Request) []byte {
+	var out bytes.Buffer
+	if json.Indent(&out, request.Body, "", "  ") != nil {
+		return []byte(fmt.Sprintf("<%d bytes of %s>", len(request.Body), request.Header.Get("Content-Type")))
+	}
+	return out.Bytes()
+}
+
+func truncate(body []byte, length int) []byte {
+	if len(body) <= length {
+		return body
+	}
+	return append(body[:length:length], "..."...)
+}
@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer answers with the given statuses in turn, then with 200.
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1))
+		if call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			io.WriteString(w, "try again")
+			return
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testSender(retries int, out io.Writer) *Sender {
+	return &Sender{Client: &http.Client{Timeout: 5 * time.Second}, Retries: retries, Backoff: time.Millisecond, Out: out}
+}
+
+func TestSenderRetriesServerErrors(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError, http.StatusBadGateway)
+	var out bytes.Buffer
+
+	body, err := testSender(3, &out).PostJSON("slack", server.URL, []byte(`{}`))
+	if err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %q", body)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+	if !strings.Contains(out.String(), "slack attempt 2 failed: unexpected status 502") {
+		t.Errorf("missing retry warning in %q", out.String())
+	}
+}
+
+func TestSenderRetriesTooManyRequestsAfterRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := testSender(1, io.Discard).PostJSON("teams", server.URL, []byte(`{}`)); err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s of Retry-After", elapsed)
+	}
+}
+
+func TestSenderDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		server, calls := statusServer(t, status, status)
+
+		_, err := testSender(3, io.Discard).PostJSON("slack", server.URL, []byte(`{}`))
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != status {
+			t.Errorf("status %d: err = %v, want a StatusError", status, err)
+		}
+		if *calls != 1 {
+			t.Errorf("status %d: calls = %d, want 1", status, *calls)
+		}
+	}
+}
+
+func TestSenderDoublesBackoff(t *testing.T) {
+	server, calls := statusServer(t, 503, 503, 503, 503)
+	var out bytes.Buffer
+	sender := testSender(3, &out)
+	sender.Backoff = 10 * time.Millisecond
+
+	_, err := sender.PostJSON("slack", server.URL, []byte(`{}`))
+	if err == nil || !strings.HasPrefix(err.Error(), "slack: unexpected status 503") {
+		t.Fatalf("err = %v, want the last status error", err)
+	}
+	if *calls != 4 {
+		t.Errorf("calls = %d, want 4", *calls)
+	}
+	for _, wait := range []string{"retrying in 10ms", "retrying in 20ms", "retrying in 40ms"} {
+		if !strings.Contains(out.String(), wait) {
+			t.Errorf("missing %q in %q", wait, out.String())
+		}
+	}
+}
+
+func TestSenderDryRun(t *testing.T) {
+	server, calls := statusServer(t)
+	var out bytes.Buffer
+	sender := testSender(0, &out)
+	sender.DryRun = true
+
+	if _, err := sender.PostJSON("slack", server.URL, []byte(`{"text":"hi"}`)); err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("dry run sent %d requests", *calls)
+	}
+	if !strings.Contains(out.String(), "[DRY RUN] slack POST request:\n{\n  \"text\": \"hi\"\n}") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+
+	upload := Request{Method: http.MethodPut, URL: server.URL, Header: http.Header{"Content-Type": {"text/html"}}, Body: []byte("<html>")}
+	out.Reset()
+	if _, err := sender.Do("s3", upload); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !strings.Contains(out.String(), "<6 bytes of text/html>") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+
+	var result struct{ OK bool }
+	if err := sender.JSON("lookup", http.MethodGet, server.URL, nil, nil, &result); err != nil || !result.OK {
+		t.Errorf("GET in dry run: ok = %v, err = %v", result.OK, err)
+	}
+	if *calls != 1 {
+		t.Errorf("calls = %d, want the GET only", *calls)
+	}
+}
+
+func TestSenderHidesURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	secretURL := server.URL + "/services/T000/B000/secret-token"
+	server.Close()
+
+	_, err := testSender(1, io.Discard).PostJSON("slack", secretURL, []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected a transport error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks the URL: %v", err)
+	}
+
+	_, err = testSender(0, io.Discard).PostJSON("teams", "http://host/\x7fsecret-token", []byte(`{}`))
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("invalid URL error = %v, want one without the URL", err)
+	}
+}
+
+func TestJSONHeaders(t *testing.T) {
+	var accept, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept, contentType = r.Header.Get("Accept"), r.Header.Get("Content-Type")
+		io.WriteString(w, `{"id":7}`)
+	}))
+	defer server.Close()
+	sender := testSender(0, io.Discard)
+
+	var result struct{ ID int }
+	if err := sender.JSON("api", http.MethodPost, server.URL, nil, map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if accept != "application/json" || contentType != "application/json" || result.ID != 7 {
+		t.Errorf("accept = %q, content type = %q, id = %d", accept, contentType, result.ID)
+	}
+
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if err := sender.JSON("api", http.MethodGet, server.URL, header, nil, nil); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if accept != "application/vnd.github+json" || contentType != "" {
+		t.Errorf("accept = %q, content type = %q", accept, contentType)
+	}
+}
@@ -0,0 +1,233 @@
+// storage/s3.go
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPresignExpiry is the longest validity SigV4 allows for a presigned URL.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// S3 addresses a bucket of Amazon S3 or an S3-compatible store such as MinIO.
+// Requests are signed with AWS Signature Version 4.
+type S3 struct {
+	// Endpoint is the base URL of the store, e.g. https://minio.internal:9000.
+	// Empty means Amazon S3 in Region.
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	// PathStyle puts the bucket in the path (endpoint/bucket/key) instead of
+	// the host name (bucket.endpoint/key). MinIO usually needs it.
+	PathStyle bool
+}
+
+// ObjectURL returns the unsigned URL of key.
+func (s S3) ObjectURL(key string) (*url.URL, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.region() + ".amazonaws.com"
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if s.Bucket == "" {
+		return nil, fmt.Errorf("no S3 bucket")
+	}
+
+	objectURL := &url.URL{Scheme: base.Scheme, Host: base.Host}
+	path := strings.TrimSuffix(base.Path, "/")
+	if s.PathStyle {
+		path += "/" + s.Bucket
+	} else {
+		objectURL.Host = s.Bucket + "." + base.Host
+	}
+	objectURL.Path = path + "/" + strings.TrimPrefix(key, "/")
+	objectURL.RawPath = escapePath(objectURL.Path)
+	return objectURL, nil
+}
+
+// PutObject returns a signed request uploading body to key.
+func (s S3) PutObject(key string, body []byte, contentType string, now time.Time) (*http.Request, error) {
+	objectURL, err := s.ObjectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := sha256.Sum256(body)
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	header.Set("X-Amz-Date", now.UTC().Format(amzDateFormat))
+	if s.SessionToken != "" {
+		header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	signed := map[string]string{"host": objectURL.Host}
+	for key := range header {
+		signed[strings.ToLower(key)] = header.Get(key)
+	}
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(signed)
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut,
+		objectURL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := s.scope(now)
+	header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, s.AccessKey, scope, signedHeaders, s.signature(canonicalRequest, scope, now)))
+
+	request, err := http.NewRequest(http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header = header
+	return request, nil
+}
+
+// PresignGet returns a URL that downloads key without credentials until expires has passed.
+func (s S3) PresignGet(key string, expires time.Duration, now time.Time) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", fmt.Errorf("presigned URL expiry must be between 1s and %s", MaxPresignExpiry)
+	}
+	objectURL, err := s.ObjectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	scope := s.scope(now)
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", s.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.UTC().Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if s.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	canonicalQuery := canonicalizeQuery(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	objectURL.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(canonicalRequest, scope, now)
+	return objectURL.String(), nil
+}
+
+func (s S3) region() string {
+	if s.Region == "" {
+		return "us-east-1"
+	}
+	return s.Region
+}
+
+// scope is the credential scope: date/region/s3/aws4_request.
+func (s S3) scope(now time.Time) string {
+	return now.UTC().Format("20060102") + "/" + s.region() + "/s3/aws4_request"
+}
+
+// signature signs the canonical request with the key derived for the scope.
+func (s S3) signature(canonicalRequest string, scope string, now time.Time) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		now.UTC().Format(amzDateFormat),
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + s.SecretKey)
+	for _, part := range []string{now.UTC().Format("20060102"), s.region(), "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalizeHeaders returns the signed header list and the canonical header block.
+func canonicalizeHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// canonicalizeQuery sorts and encodes query parameters as SigV4 expects.
+func canonicalizeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapePath encodes every path segment, keeping the slashes.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape percent-encodes everything except the RFC 3986 unreserved characters.
+func escape(value string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || b == '-' || b == '.' || b == '_' || b == '~' {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
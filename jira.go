@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"pipeline-html-generator/internal/analysis"
+	"pipeline-html-generator/internal/models"
+	"pipeline-html-generator/internal/notify"
+	"strings"
+)
+
+// reportToJira comments on every Jira issue referenced by the commits of the
+// execution and, on success, moves them through jira_transition. It returns
+// the issue keys found. Failures are reported as warnings.
+func reportToJira(config Config, pipeline models.Pipeline, sender *notify.Sender) []string {
+	if config.JiraURL == "" || config.JiraToken == "" {
+		return nil
+	}
+
+	fmt.Println(lineBreak)
+	fmt.Println("| \033[1;36mUpdating Jira issues...\033[0m")
+	fmt.Println(lineBreak)
+
+	issues := analysis.IssueKeys(commitMessages(config, pipeline), config.JiraProjects)
+	fmt.Printf("| \033[1;36mJira issues referenced:\033[0m \033[1;32m%s\033[0m\n", strings.Join(issues, ", "))
+
+	jira := notify.Jira{BaseURL: config.JiraURL, Username: config.JiraUsername, Token: config.JiraToken, Sender: sender}
+	comment := jiraComment(config, pipeline)
+	transition := config.JiraTransition != "" && notify.Outcome(pipeline.Status) == notify.OutcomeSuccess
+	for _, issue := range issues {
+		if err := jira.Comment(issue, comment); err != nil {
+			fmt.Println("| \033[33m[WARNING] - Error commenting on Jira issue: ", err, "\033[0m")
+			continue
+		}
+		if !transition {
+			continue
+		}
+		err := jira.Transition(issue, config.JiraTransition)
+		switch {
+		case errors.Is(err, notify.ErrNoTransition):
+			fmt.Printf("| \033[33m[WARNING] - %s has no %q transition, it may already be there\033[0m\n", issue, config.JiraTransition)
+		case err != nil:
+			fmt.Println("| \033[33m[WARNING] - Error transitioning Jira issue: ", err, "\033[0m")
+		default:
+			fmt.Printf("| \033[1;36m%s moved to\033[0m \033[1;32m%s\033[0m\n", issue, config.JiraTransition)
+		}
+	}
+	fmt.Println(lineBreak)
+	return issues
+}
+
+// commitMessages returns the messages of the CI commit info and, with
+// jira_commit_range, of the commits in that range of the local clone.
+func commitMessages(config Config, pipeline models.Pipeline) []string {
+	var messages []string
+	for _, commit := range pipeline.Commits {
+		messages = append(messages, commit.Message)
+	}
+	if config.JiraCommitRange == "" {
+		return messages
+	}
+
+	older, newer, ok := strings.Cut(config.JiraCommitRange, "..")
+	if !ok {
+		fmt.Printf("| \033[33m[WARNING] - jira_commit_range %q is not in the older..newer form\033[0m\n", config.JiraCommitRange)
+		return messages
+	}
+	files, err := GetCommitInfo(older, newer)
+	if err != nil {
+		fmt.Println("| \033[33m[WARNING] - Error reading the commits of jira_commit_range: ", err, "\033[0m")
+		return messages
+	}
+	for _, file := range files {
+		for _, commit := range file.CommitDetails {
+			messages = append(messages, commit.Title+"\n"+commit.Body)
+		}
+	}
+	return messages
+}
+
+// jiraComment describes the execution in Jira wiki markup.
+func jiraComment(config Config, pipeline models.Pipeline) string {
+	var comment strings.Builder
+	fmt.Fprintf(&comment, "Harness pipeline *%s*: *%s*", pipeline.Name, pipeline.Status)
+	if pipeline.Duration != "" {
+		fmt.Fprintf(&comment, " in %s", pipeline.Duration)
+	}
+	environments := pipeline.Environments
+	if config.JiraEnvironment != "" {
+		environments = []string{config.JiraEnvironment}
+	}
+	if len(environments) > 0 {
+		fmt.Fprintf(&comment, "\nEnvironment: %s", strings.Join(environments, ", "))
+	}
+	if pipeline.ExecutionLink != "" {
+		fmt.Fprintf(&comment, "\n[View execution %s|%s]", pipeline.ExecutionId, pipeline.ExecutionLink)
+	}
+	return comment.String()
+}
@@ -14,6 +14,37 @@ func main() {
 	app.Name = "Harness-Pipeline-Status-Reporter"
 	app.Usage = "CLI tool to report pipeline status to Harness"
 	app.Action = run
+	app.Commands = []cli.Command{
+		{
+			Name:   "dora",
+			Usage:  "Compute the DORA metrics of a deployment pipeline from its execution history",
+			Action: runDora,
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:   "window_days",
+					Usage:  "Number of days covered by the metrics",
+					Value:  30,
+					EnvVar: "PLUGIN_DORA_WINDOW_DAYS",
+				},
+				cli.IntFlag{
+					Name:   "page_size",
+					Usage:  "Number of executions fetched per request while paging through the window",
+					Value:  100,
+					EnvVar: "PLUGIN_DORA_PAGE_SIZE",
+				},
+				cli.StringSliceFlag{
+					Name:   "output",
+					Usage:  "Reports to write as format=path (html, json, markdown). Default: html=dora.html,json=dora.json,markdown=dora.md",
+					EnvVar: "PLUGIN_DORA_OUTPUT",
+				},
+				cli.StringFlag{
+					Name:   "output_dir",
+					Usage:  "Directory the relative report paths are written to",
+					EnvVar: "PLUGIN_OUTPUT_DIR",
+				},
+			},
+		},
+	}
 	app.Version = fmt.Sprintf("1.0.%s", build)
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
@@ -72,6 +103,395 @@ func main() {
 			Usage:  "Skip skipped stages/steps",
 			EnvVar: "PLUGIN_SKIP_SKIPPED",
 		},
+		cli.StringFlag{
+			Name:   "template",
+			Usage:  "Path to a custom html/template file used instead of the embedded dashboard template",
+			EnvVar: "PLUGIN_TEMPLATE",
+		},
+		cli.StringFlag{
+			Name:   "template_dir",
+			Usage:  "Directory with template overrides (dashboard.html, commit_report.html) and *.tmpl partials",
+			EnvVar: "PLUGIN_TEMPLATE_DIR",
+		},
+		cli.StringFlag{
+			Name:   "theme",
+			Usage:  "Report theme: light, dark or high-contrast",
+			Value:  "light",
+			EnvVar: "PLUGIN_THEME",
+		},
+		cli.StringFlag{
+			Name:   "theme_file",
+			Usage:  "JSON file overriding the palette, logo, font and status colors of the selected theme",
+			EnvVar: "PLUGIN_THEME_FILE",
+		},
+		cli.StringFlag{
+			Name:   "html_mode",
+			Usage:  "HTML renderer: rich (dashboard) or email (table layout with inline styles for notification emails)",
+			Value:  "rich",
+			EnvVar: "PLUGIN_HTML_MODE",
+		},
+		cli.StringFlag{
+			Name:   "badge_label",
+			Usage:  "Left side of the status badge, supports {pipeline}, {status}, {duration} and {executionId}",
+			Value:  "{pipeline}",
+			EnvVar: "PLUGIN_BADGE_LABEL",
+		},
+		cli.StringFlag{
+			Name:   "badge_text",
+			Usage:  "Right side of the status badge, supports {pipeline}, {status}, {duration} and {executionId}",
+			Value:  "{status}",
+			EnvVar: "PLUGIN_BADGE_TEXT",
+		},
+		cli.BoolFlag{
+			Name:   "badge_duration",
+			Usage:  "Append the pipeline duration to the status badge",
+			EnvVar: "PLUGIN_BADGE_DURATION",
+		},
+		cli.StringSliceFlag{
+			Name:   "output",
+			Usage:  "Comma-separated format=path outputs (html, email, summary, json, markdown, junit, text, badge, flaky, slack, teams). Default: html=pipeline.html",
+			EnvVar: "PLUGIN_OUTPUT",
+		},
+		cli.StringFlag{
+			Name:   "output_dir",
+			Usage:  "Directory for relative output paths. Paths accept {executionId}, {pipeline}, {status} and {format}",
+			EnvVar: "PLUGIN_OUTPUT_DIR",
+		},
+		cli.BoolFlag{
+			Name:   "compare",
+			Usage:  "Compare the execution with the previous one of the same pipeline, branch and repo",
+			EnvVar: "PLUGIN_COMPARE",
+		},
+		cli.StringSliceFlag{
+			Name:   "compare_status",
+			Usage:  "Statuses the previous execution must have. Default: Success (compare with the last green execution)",
+			EnvVar: "PLUGIN_COMPARE_STATUS",
+		},
+		cli.IntFlag{
+			Name:   "compare_depth",
+			Usage:  "Number of recent executions searched for the previous one",
+			Value:  10,
+			EnvVar: "PLUGIN_COMPARE_DEPTH",
+		},
+		cli.BoolFlag{
+			Name:   "flaky",
+			Usage:  "Detect flaky steps across the recent executions of the pipeline",
+			EnvVar: "PLUGIN_FLAKY",
+		},
+		cli.IntFlag{
+			Name:   "flaky_depth",
+			Usage:  "Number of recent executions analysed for flaky steps",
+			Value:  20,
+			EnvVar: "PLUGIN_FLAKY_DEPTH",
+		},
+		cli.Float64Flag{
+			Name:   "flaky_min_score",
+			Usage:  "Minimum flakiness score (0-100) for a step to be reported as flaky",
+			EnvVar: "PLUGIN_FLAKY_MIN_SCORE",
+		},
+		cli.BoolFlag{
+			Name:   "trend",
+			Usage:  "Add duration and success trend charts of the recent executions to the reports",
+			EnvVar: "PLUGIN_TREND",
+		},
+		cli.IntFlag{
+			Name:   "trend_depth",
+			Usage:  "Number of recent executions shown in the trend charts",
+			Value:  20,
+			EnvVar: "PLUGIN_TREND_DEPTH",
+		},
+		cli.StringFlag{
+			Name:   "budgets_file",
+			Usage:  "JSON file with maximum durations for pipeline, stage and step name patterns",
+			EnvVar: "PLUGIN_BUDGETS_FILE",
+		},
+		cli.BoolFlag{
+			Name:   "budget_fail",
+			Usage:  "Exit with a non-zero code when a duration budget is exceeded",
+			EnvVar: "PLUGIN_BUDGET_FAIL",
+		},
+		cli.StringFlag{
+			Name:   "slack_webhook",
+			Usage:  "Slack incoming webhook URL the execution summary is posted to",
+			EnvVar: "PLUGIN_SLACK_WEBHOOK",
+		},
+		cli.StringFlag{
+			Name:   "teams_webhook",
+			Usage:  "Microsoft Teams incoming webhook or Workflows URL the execution summary is posted to",
+			EnvVar: "PLUGIN_TEAMS_WEBHOOK",
+		},
+		cli.StringFlag{
+			Name:   "smtp_host",
+			Usage:  "SMTP server the report is emailed through",
+			EnvVar: "PLUGIN_SMTP_HOST",
+		},
+		cli.IntFlag{
+			Name:   "smtp_port",
+			Usage:  "SMTP server port, usually 587 with starttls and 465 with tls",
+			Value:  587,
+			EnvVar: "PLUGIN_SMTP_PORT",
+		},
+		cli.StringFlag{
+			Name:   "smtp_username",
+			Usage:  "SMTP user name, leave empty for relays without authentication",
+			EnvVar: "PLUGIN_SMTP_USERNAME",
+		},
+		cli.StringFlag{
+			Name:   "smtp_password",
+			Usage:  "SMTP password",
+			EnvVar: "PLUGIN_SMTP_PASSWORD",
+		},
+		cli.StringFlag{
+			Name:   "smtp_security",
+			Usage:  "SMTP connection security: starttls, tls or none",
+			Value:  "starttls",
+			EnvVar: "PLUGIN_SMTP_SECURITY",
+		},
+		cli.StringFlag{
+			Name:   "smtp_from",
+			Usage:  "Sender address, e.g. \"CI <ci@example.com>\"",
+			EnvVar: "PLUGIN_SMTP_FROM",
+		},
+		cli.StringSliceFlag{
+			Name:   "smtp_to",
+			Usage:  "Comma-separated recipient addresses",
+			EnvVar: "PLUGIN_SMTP_TO",
+		},
+		cli.StringFlag{
+			Name:   "smtp_subject",
+			Usage:  "Email subject, supports {pipeline}, {status}, {duration} and {executionId}",
+			Value:  "[{status}] {pipeline}",
+			EnvVar: "PLUGIN_SMTP_SUBJECT",
+		},
+		cli.BoolFlag{
+			Name:   "smtp_attach_html",
+			Usage:  "Attach the HTML report instead of showing the email layout inline",
+			EnvVar: "PLUGIN_SMTP_ATTACH_HTML",
+		},
+		cli.StringFlag{
+			Name:   "s3_bucket",
+			Usage:  "Bucket the outputs are uploaded to",
+			EnvVar: "PLUGIN_S3_BUCKET",
+		},
+		cli.StringFlag{
+			Name:   "s3_endpoint",
+			Usage:  "Endpoint of an S3-compatible store, e.g. https://minio.example.com:9000. Default: Amazon S3",
+			EnvVar: "PLUGIN_S3_ENDPOINT",
+		},
+		cli.StringFlag{
+			Name:   "s3_region",
+			Usage:  "Region of the bucket",
+			Value:  "us-east-1",
+			EnvVar: "PLUGIN_S3_REGION, AWS_REGION",
+		},
+		cli.StringFlag{
+			Name:   "s3_access_key",
+			Usage:  "Access key ID",
+			EnvVar: "PLUGIN_S3_ACCESS_KEY, AWS_ACCESS_KEY_ID",
+		},
+		cli.StringFlag{
+			Name:   "s3_secret_key",
+			Usage:  "Secret access key",
+			EnvVar: "PLUGIN_S3_SECRET_KEY, AWS_SECRET_ACCESS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "s3_session_token",
+			Usage:  "Session token of temporary credentials",
+			EnvVar: "PLUGIN_S3_SESSION_TOKEN, AWS_SESSION_TOKEN",
+		},
+		cli.BoolFlag{
+			Name:   "s3_path_style",
+			Usage:  "Address the bucket in the path instead of the host name, as MinIO usually needs",
+			EnvVar: "PLUGIN_S3_PATH_STYLE",
+		},
+		cli.StringFlag{
+			Name:   "s3_key",
+			Usage:  "Object key of each output, supports {pipeline}, {executionId}, {status}, {format} and {file} (index.html for the html output)",
+			Value:  "{pipeline}/{executionId}/{file}",
+			EnvVar: "PLUGIN_S3_KEY",
+		},
+		cli.BoolFlag{
+			Name:   "s3_presign",
+			Usage:  "Export a presigned URL of the report instead of its plain object URL",
+			EnvVar: "PLUGIN_S3_PRESIGN",
+		},
+		cli.StringFlag{
+			Name:   "s3_presign_expiry",
+			Usage:  "Validity of the presigned URL, at most 168h",
+			Value:  "168h",
+			EnvVar: "PLUGIN_S3_PRESIGN_EXPIRY",
+		},
+		cli.StringFlag{
+			Name:   "commit_sha",
+			Usage:  "Commit the statuses are set on. Default: the commit of the CI commit info",
+			EnvVar: "PLUGIN_COMMIT_SHA, DRONE_COMMIT_SHA",
+		},
+		cli.IntFlag{
+			Name:   "pull_request",
+			Usage:  "Number of the pull request commented on",
+			EnvVar: "PLUGIN_PULL_REQUEST, DRONE_PULL_REQUEST",
+		},
+		cli.StringFlag{
+			Name:   "status_context",
+			Usage:  "Name identifying the commit status of this pipeline. Default: harness/<pipeline_id>",
+			EnvVar: "PLUGIN_STATUS_CONTEXT",
+		},
+		cli.StringFlag{
+			Name:   "github_token",
+			Usage:  "GitHub token with permission to write commit statuses and pull request comments",
+			EnvVar: "PLUGIN_GITHUB_TOKEN, GITHUB_TOKEN",
+		},
+		cli.StringFlag{
+			Name:   "github_api_url",
+			Usage:  "GitHub API base URL, https://<host>/api/v3 for GitHub Enterprise Server",
+			Value:  "https://api.github.com",
+			EnvVar: "PLUGIN_GITHUB_API_URL",
+		},
+		cli.StringFlag{
+			Name:   "github_repo",
+			Usage:  "GitHub repository as owner/name",
+			EnvVar: "PLUGIN_GITHUB_REPO, DRONE_REPO",
+		},
+		cli.BoolFlag{
+			Name:   "github_status",
+			Usage:  "Set the execution status as GitHub commit status",
+			EnvVar: "PLUGIN_GITHUB_STATUS",
+		},
+		cli.BoolFlag{
+			Name:   "github_comment",
+			Usage:  "Create or update a pull request comment with the Markdown summary",
+			EnvVar: "PLUGIN_GITHUB_COMMENT",
+		},
+		cli.StringFlag{
+			Name:   "bitbucket_url",
+			Usage:  "Bitbucket API base URL; URLs not ending in /2.0 are taken as a Data Center instance, e.g. https://bitbucket.example.com",
+			Value:  "https://api.bitbucket.org/2.0",
+			EnvVar: "PLUGIN_BITBUCKET_URL",
+		},
+		cli.StringFlag{
+			Name:   "bitbucket_username",
+			Usage:  "User name for basic authentication with an app password. Leave empty to send the token as bearer token",
+			EnvVar: "PLUGIN_BITBUCKET_USERNAME",
+		},
+		cli.StringFlag{
+			Name:   "bitbucket_token",
+			Usage:  "Bitbucket access token, HTTP access token or app password",
+			EnvVar: "PLUGIN_BITBUCKET_TOKEN",
+		},
+		cli.StringFlag{
+			Name:   "bitbucket_workspace",
+			Usage:  "Workspace (Bitbucket Cloud) or project key (Data Center) of the repository",
+			EnvVar: "PLUGIN_BITBUCKET_WORKSPACE",
+		},
+		cli.StringFlag{
+			Name:   "bitbucket_repo",
+			Usage:  "Repository slug",
+			EnvVar: "PLUGIN_BITBUCKET_REPO",
+		},
+		cli.BoolFlag{
+			Name:   "bitbucket_status",
+			Usage:  "Set the execution status as Bitbucket build status",
+			EnvVar: "PLUGIN_BITBUCKET_STATUS",
+		},
+		cli.BoolFlag{
+			Name:   "bitbucket_comment",
+			Usage:  "Create or update a pull request comment with the Markdown summary",
+			EnvVar: "PLUGIN_BITBUCKET_COMMENT",
+		},
+		cli.StringFlag{
+			Name:   "gitlab_url",
+			Usage:  "GitLab base URL, e.g. https://gitlab.example.com for a self-managed instance",
+			Value:  "https://gitlab.com",
+			EnvVar: "PLUGIN_GITLAB_URL",
+		},
+		cli.StringFlag{
+			Name:   "gitlab_token",
+			Usage:  "GitLab personal, project or group access token with the api scope",
+			EnvVar: "PLUGIN_GITLAB_TOKEN",
+		},
+		cli.StringFlag{
+			Name:   "gitlab_project",
+			Usage:  "GitLab project ID or path, e.g. acme/payments",
+			EnvVar: "PLUGIN_GITLAB_PROJECT",
+		},
+		cli.BoolFlag{
+			Name:   "gitlab_status",
+			Usage:  "Set the execution status as external GitLab commit status",
+			EnvVar: "PLUGIN_GITLAB_STATUS",
+		},
+		cli.BoolFlag{
+			Name:   "gitlab_comment",
+			Usage:  "Create or update a merge request note with the Markdown summary",
+			EnvVar: "PLUGIN_GITLAB_COMMENT",
+		},
+		cli.StringFlag{
+			Name:   "jira_url",
+			Usage:  "Jira base URL, e.g. https://acme.atlassian.net",
+			EnvVar: "PLUGIN_JIRA_URL",
+		},
+		cli.StringFlag{
+			Name:   "jira_username",
+			Usage:  "Jira Cloud account email for basic authentication with an API token. Leave empty to send the token as bearer personal access token",
+			EnvVar: "PLUGIN_JIRA_USERNAME",
+		},
+		cli.StringFlag{
+			Name:   "jira_token",
+			Usage:  "Jira API token (Cloud) or personal access token (Data Center)",
+			EnvVar: "PLUGIN_JIRA_TOKEN",
+		},
+		cli.StringSliceFlag{
+			Name:   "jira_projects",
+			Usage:  "Comma-separated project keys the issue keys must belong to. Default: any key",
+			EnvVar: "PLUGIN_JIRA_PROJECTS",
+		},
+		cli.StringFlag{
+			Name:   "jira_transition",
+			Usage:  "Transition, or target status, applied to the issues when the execution succeeds",
+			EnvVar: "PLUGIN_JIRA_TRANSITION",
+		},
+		cli.StringFlag{
+			Name:   "jira_environment",
+			Usage:  "Environment named in the comments. Default: the environments of the CD stages",
+			EnvVar: "PLUGIN_JIRA_ENVIRONMENT",
+		},
+		cli.StringFlag{
+			Name:   "jira_commit_range",
+			Usage:  "Also read issue keys from the local commits in this older..newer range",
+			EnvVar: "PLUGIN_JIRA_COMMIT_RANGE",
+		},
+		cli.StringSliceFlag{
+			Name:   "output_vars",
+			Usage:  "Comma-separated output variables to export, a trailing * matches by prefix (e.g. PIPELINE_STEPS_*). Default: all",
+			EnvVar: "PLUGIN_OUTPUT_VARS",
+		},
+		cli.StringFlag{
+			Name:   "output_vars_prefix",
+			Usage:  "Prefix added to the names of the exported output variables",
+			EnvVar: "PLUGIN_OUTPUT_VARS_PREFIX",
+		},
+		cli.StringFlag{
+			Name:   "html_report_encoding",
+			Usage:  "Encoding of the HTML_REPORT output variable: raw, minified, base64 or gzip+base64",
+			Value:  "minified",
+			EnvVar: "PLUGIN_HTML_REPORT_ENCODING",
+		},
+		cli.IntFlag{
+			Name:   "html_report_max_size",
+			Usage:  "Maximum size of HTML_REPORT in bytes. Bigger dashboards fall back to the compact summary. 0 disables the limit",
+			Value:  131072,
+			EnvVar: "PLUGIN_HTML_REPORT_MAX_SIZE",
+		},
+		cli.IntFlag{
+			Name:   "notify_retries",
+			Usage:  "Number of retries of a notification after a network error, 429 or 5xx answer",
+			Value:  3,
+			EnvVar: "PLUGIN_NOTIFY_RETRIES",
+		},
+		cli.BoolFlag{
+			Name:   "notify_dry_run",
+			Usage:  "Print the notification payloads instead of sending them",
+			EnvVar: "PLUGIN_NOTIFY_DRY_RUN",
+		},
 	}
 	app.Run(os.Args)
 }
@@ -82,17 +502,7 @@ func run(c *cli.Context) {
 		os.Exit(1)
 	}
 
-	config := Config{
-		AccID:         c.String("acc_id"),
-		OrgID:         c.String("org_id"),
-		ProjectID:     c.String("project_id"),
-		PipelineID:    c.String("pipeline_id"),
-		StatusList:    c.StringSlice("status_list"),
-		RepoName:      c.String("repo_name"),
-		Branch:        c.String("branch"),
-		ServiceName:   c.String("service_name"),
-		HarnessSecret: c.String("harness_secret"),
-	}
+	config := newConfig(c)
 
 	plugin := Plugin{Config: config}
 	if err := plugin.Exec(); err != nil {
@@ -100,3 +510,107 @@ func run(c *cli.Context) {
 		os.Exit(1)
 	}
 }
+
+// runDora runs the dora command. The Harness settings come from the global flags.
+func runDora(c *cli.Context) {
+	config := newConfig(c.Parent())
+	config.DoraWindowDays = c.Int("window_days")
+	config.DoraPageSize = c.Int("page_size")
+	config.Outputs = c.StringSlice("output")
+	config.OutputDir = c.String("output_dir")
+
+	plugin := Plugin{Config: config}
+	if err := plugin.ExecDora(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// newConfig reads the global flags.
+func newConfig(c *cli.Context) Config {
+	return Config{
+		AccID:              c.String("acc_id"),
+		OrgID:              c.String("org_id"),
+		ProjectID:          c.String("project_id"),
+		PipelineID:         c.String("pipeline_id"),
+		StatusList:         c.StringSlice("status_list"),
+		RepoName:           c.String("repo_name"),
+		Branch:             c.String("branch"),
+		ServiceName:        c.String("service_name"),
+		HarnessSecret:      c.String("harness_secret"),
+		TemplatePath:       c.String("template"),
+		TemplateDir:        c.String("template_dir"),
+		Theme:              c.String("theme"),
+		ThemeFile:          c.String("theme_file"),
+		HTMLMode:           c.String("html_mode"),
+		BadgeLabel:         c.String("badge_label"),
+		BadgeText:          c.String("badge_text"),
+		BadgeDuration:      c.Bool("badge_duration"),
+		Outputs:            c.StringSlice("output"),
+		OutputDir:          c.String("output_dir"),
+		Compare:            c.Bool("compare"),
+		CompareStatus:      c.StringSlice("compare_status"),
+		CompareDepth:       c.Int("compare_depth"),
+		Flaky:              c.Bool("flaky"),
+		FlakyDepth:         c.Int("flaky_depth"),
+		FlakyMinScore:      c.Float64("flaky_min_score"),
+		Trend:              c.Bool("trend"),
+		TrendDepth:         c.Int("trend_depth"),
+		BudgetsFile:        c.String("budgets_file"),
+		BudgetFail:         c.Bool("budget_fail"),
+		SlackWebhook:       c.String("slack_webhook"),
+		TeamsWebhook:       c.String("teams_webhook"),
+		SMTPHost:           c.String("smtp_host"),
+		SMTPPort:           c.Int("smtp_port"),
+		SMTPUsername:       c.String("smtp_username"),
+		SMTPPassword:       c.String("smtp_password"),
+		SMTPSecurity:       c.String("smtp_security"),
+		SMTPFrom:           c.String("smtp_from"),
+		SMTPTo:             c.StringSlice("smtp_to"),
+		SMTPSubject:        c.String("smtp_subject"),
+		SMTPAttachHTML:     c.Bool("smtp_attach_html"),
+		S3Bucket:           c.String("s3_bucket"),
+		S3Endpoint:         c.String("s3_endpoint"),
+		S3Region:           c.String("s3_region"),
+		S3AccessKey:        c.String("s3_access_key"),
+		S3SecretKey:        c.String("s3_secret_key"),
+		S3SessionToken:     c.String("s3_session_token"),
+		S3PathStyle:        c.Bool("s3_path_style"),
+		S3Key:              c.String("s3_key"),
+		S3Presign:          c.Bool("s3_presign"),
+		S3PresignExpiry:    c.String("s3_presign_expiry"),
+		CommitSha:          c.String("commit_sha"),
+		PullRequest:        c.Int("pull_request"),
+		StatusContext:      c.String("status_context"),
+		GitHubToken:        c.String("github_token"),
+		GitHubAPIURL:       c.String("github_api_url"),
+		GitHubRepo:         c.String("github_repo"),
+		GitHubStatus:       c.Bool("github_status"),
+		GitHubComment:      c.Bool("github_comment"),
+		BitbucketURL:       c.String("bitbucket_url"),
+		BitbucketUsername:  c.String("bitbucket_username"),
+		BitbucketToken:     c.String("bitbucket_token"),
+		BitbucketWorkspace: c.String("bitbucket_workspace"),
+		BitbucketRepo:      c.String("bitbucket_repo"),
+		BitbucketStatus:    c.Bool("bitbucket_status"),
+		BitbucketComment:   c.Bool("bitbucket_comment"),
+		GitLabURL:          c.String("gitlab_url"),
+		GitLabToken:        c.String("gitlab_token"),
+		GitLabProject:      c.String("gitlab_project"),
+		GitLabStatus:       c.Bool("gitlab_status"),
+		GitLabComment:      c.Bool("gitlab_comment"),
+		JiraURL:            c.String("jira_url"),
+		JiraUsername:       c.String("jira_username"),
+		JiraToken:          c.String("jira_token"),
+		JiraProjects:       c.StringSlice("jira_projects"),
+		JiraTransition:     c.String("jira_transition"),
+		JiraEnvironment:    c.String("jira_environment"),
+		JiraCommitRange:    c.String("jira_commit_range"),
+		OutputVars:         c.StringSlice("output_vars"),
+		OutputVarsPrefix:   c.String("output_vars_prefix"),
+		HTMLReportEncoding: c.String("html_report_encoding"),
+		HTMLReportMaxSize:  c.Int("html_report_max_size"),
+		NotifyRetries:      c.Int("notify_retries"),
+		NotifyDryRun:       c.Bool("notify_dry_run"),
+	}
+}
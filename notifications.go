@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"pipeline-html-generator/internal/notify"
+	"strings"
+)
+
+// defaultEmailSubject is used when smtp_subject is empty.
+const defaultEmailSubject = "[{status}] {pipeline}"
+
+// webhookNotifier posts a rendered output format to a chat webhook.
+type webhookNotifier struct {
+	name    string
+	format  string
+	webhook string
+}
+
+// sendNotifications delivers the rendered report to the configured chat webhooks
+// and email recipients. A failed delivery is reported as a warning and does not
+// fail the plugin.
+func sendNotifications(config Config, pipeline models.Pipeline, outputs *outputSet, sender *notify.Sender) {
+	notifiers := []webhookNotifier{
+		{name: "Slack", format: "slack", webhook: config.SlackWebhook},
+		{name: "Teams", format: "teams", webhook: config.TeamsWebhook},
+	}
+
+	for _, notifier := range notifiers {
+		if notifier.webhook == "" {
+			continue
+		}
+		fmt.Println(lineBreak)
+		fmt.Printf("| \033[1;36mSending %s notification...\033[0m\n", notifier.name)
+		fmt.Println(lineBreak)
+		payload, err := outputs.render(notifier.format)
+		if err == nil {
+			_, err = sender.PostJSON(notifier.format, notifier.webhook, payload)
+		}
+		if err != nil {
+			fmt.Printf("| \033[33m[WARNING] - Error sending %s notification: %v\033[0m\n", notifier.name, err)
+		} else if !config.NotifyDryRun {
+			fmt.Printf("| \033[1;36m%s notification sent\033[0m\n", notifier.name)
+		}
+		fmt.Println(lineBreak)
+	}
+
+	if config.SMTPHost != "" && len(config.SMTPTo) > 0 {
+		fmt.Println(lineBreak)
+		fmt.Println("| \033[1;36mSending email notification...\033[0m")
+		fmt.Println(lineBreak)
+		err := sendEmail(config, pipeline, outputs, sender)
+		if err != nil {
+			fmt.Printf("| \033[33m[WARNING] - Error sending email notification: %v\033[0m\n", err)
+		} else if !config.NotifyDryRun {
+			fmt.Printf("| \033[1;36mEmail sent to %d recipients\033[0m\n", len(config.SMTPTo))
+		}
+		fmt.Println(lineBreak)
+	}
+}
+
+// sendEmail mails the text report with the HTML report inline, using the email
+// safe layout, or attached, using the html output.
+func sendEmail(config Config, pipeline models.Pipeline, outputs *outputSet, sender *notify.Sender) error {
+	text, err := outputs.render("text")
+	if err != nil {
+		return err
+	}
+	htmlFormat := "email"
+	if config.SMTPAttachHTML {
+		htmlFormat = "html"
+	}
+	html, err := outputs.render(htmlFormat)
+	if err != nil {
+		return err
+	}
+
+	subject := config.SMTPSubject
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+	server := notify.SMTPServer{
+		Host:     config.SMTPHost,
+		Port:     config.SMTPPort,
+		Username: config.SMTPUsername,
+		Password: config.SMTPPassword,
+		Security: config.SMTPSecurity,
+	}
+	message := notify.Mail{
+		From:           config.SMTPFrom,
+		To:             config.SMTPTo,
+		Subject:        expandSubject(subject, pipeline),
+		Text:           text,
+		HTML:           html,
+		AttachHTML:     config.SMTPAttachHTML,
+		AttachmentName: expandOutputPattern("{pipeline}-{executionId}.html", htmlFormat, pipeline),
+	}
+	return sender.SendMail("email", server, message)
+}
+
+// expandSubject fills in the {pipeline}, {status}, {duration} and {executionId} placeholders.
+func expandSubject(subject string, pipeline models.Pipeline) string {
+	return strings.NewReplacer(
+		"{pipeline}", pipeline.Name,
+		"{status}", pipeline.Status,
+		"{duration}", pipeline.Duration,
+		"{executionId}", pipeline.ExecutionId,
+	).Replace(subject)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	htmlgenerator "pipeline-html-generator/internal/generators"
+	"pipeline-html-generator/internal/models"
+	"regexp"
+	"strings"
+)
+
+// defaultOutputs are written when no --output is given.
+var defaultOutputs = []string{"html=pipeline.html"}
+
+type outputSpec struct {
+	Format string
+	Path   string
+}
+
+// outputSet renders each format once, so the same document can be written to
+// a file and exported as an output variable.
+type outputSet struct {
+	pipeline models.Pipeline
+	options  htmlgenerator.Options
+	rendered map[string][]byte
+}
+
+func newOutputSet(pipeline models.Pipeline, options htmlgenerator.Options) *outputSet {
+	return &outputSet{pipeline: pipeline, options: options, rendered: map[string][]byte{}}
+}
+
+func (o *outputSet) render(format string) ([]byte, error) {
+	if output, ok := o.rendered[format]; ok {
+		return output, nil
+	}
+	renderer, err := htmlgenerator.Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	output, err := renderer.Render(o.pipeline, o.options)
+	if err != nil {
+		return nil, err
+	}
+	o.rendered[format] = output
+	return output, nil
+}
+
+// requestsFormat reports whether an --output entry asks for format.
+func requestsFormat(entries []string, format string) bool {
+	for _, entry := range entries {
+		name, _, _ := strings.Cut(entry, "=")
+		if strings.EqualFold(strings.TrimSpace(name), format) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOutputs turns "format=path" (or just "format") entries into output specs.
+// Paths are relative to outputDir and may use the {executionId}, {pipeline},
+// {status} and {format} placeholders.
+func parseOutputs(entries []string, outputDir string, pipeline models.Pipeline) ([]outputSpec, error) {
+	if len(entries) == 0 {
+		entries = defaultOutputs
+	}
+
+	var specs []outputSpec
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		format, path, _ := strings.Cut(entry, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		renderer, err := htmlgenerator.Lookup(format)
+		if err != nil {
+			return nil, err
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			path = renderer.DefaultFilename()
+		}
+		path = expandOutputPattern(path, format, pipeline)
+		if outputDir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(expandOutputPattern(outputDir, format, pipeline), path)
+		}
+		specs = append(specs, outputSpec{Format: format, Path: path})
+	}
+
+	return specs, nil
+}
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func expandOutputPattern(pattern string, format string, pipeline models.Pipeline) string {
+	return strings.NewReplacer(
+		"{executionId}", unsafePathChars.ReplaceAllString(pipeline.ExecutionId, "_"),
+		"{pipeline}", unsafePathChars.ReplaceAllString(pipeline.Name, "_"),
+		"{status}", unsafePathChars.ReplaceAllString(pipeline.Status, "_"),
+		"{format}", format,
+	).Replace(pattern)
+}
+
+// writeOutputs renders and writes every requested output.
+func writeOutputs(outputs *outputSet, specs []outputSpec) error {
+	for _, spec := range specs {
+		content, err := outputs.render(spec.Format)
+		if err != nil {
+			return fmt.Errorf("error rendering %s output: %w", spec.Format, err)
+		}
+		if err := createDirIfNotExists(spec.Path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(spec.Path, content, 0644); err != nil {
+			return err
+		}
+
+		fmt.Println(lineBreak)
+		fmt.Printf("| \033[1;36mPipeline %s output saved to %s\033[0m\n", spec.Format, spec.Path)
+		fmt.Println(lineBreak)
+	}
+	return nil
+}
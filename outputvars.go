@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"pipeline-html-generator/internal/models"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// countedStatuses always get a count variable, so consumers can rely on them
+// even when no stage or step ended with that status.
+var countedStatuses = []string{"Success", "Failed", "Skipped", "Aborted"}
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// durationSeconds returns the elapsed seconds of the execution, up to now for running ones.
+func durationSeconds(pipeline models.Pipeline, now time.Time) string {
+	if pipeline.StartMs == 0 {
+		return ""
+	}
+	endMs := pipeline.EndMs
+	if endMs == 0 {
+		endMs = now.UnixMilli()
+	}
+	return strconv.FormatInt((endMs-pipeline.StartMs)/1000, 10)
+}
+
+// addStatusCounts adds a <prefix>_<STATUS> variable counting every status,
+// e.g. PIPELINE_STEPS_IGNORE_FAILED for IgnoreFailed. Nodes without a status
+// are counted as UNKNOWN.
+func addStatusCounts(vars map[string]string, prefix string, statuses []string) {
+	counts := map[string]int{}
+	for _, status := range countedStatuses {
+		counts[status] = 0
+	}
+	for _, status := range statuses {
+		if status == "" {
+			status = "Unknown"
+		}
+		counts[status]++
+	}
+	for status, count := range counts {
+		vars[prefix+"_"+statusVariable(status)] = strconv.Itoa(count)
+	}
+}
+
+// statusVariable turns a Harness status into a variable name suffix: AbortedByFreeze becomes ABORTED_BY_FREEZE.
+func statusVariable(status string) string {
+	var name strings.Builder
+	for i, r := range status {
+		if i > 0 && unicode.IsUpper(r) {
+			name.WriteByte('_')
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			name.WriteRune(unicode.ToUpper(r))
+		} else {
+			name.WriteByte('_')
+		}
+	}
+	return name.String()
+}
+
+// addReportPaths adds a PIPELINE_REPORT_<FORMAT> variable with the path of
+// every output written, the first one when a format is written twice.
+func addReportPaths(vars map[string]string, specs []outputSpec) {
+	for _, spec := range specs {
+		name := "PIPELINE_REPORT_" + statusVariable(spec.Format)
+		if _, ok := vars[name]; !ok {
+			vars[name] = spec.Path
+		}
+	}
+}
+
+// checkOutputVarsPrefix rejects a prefix that would produce invalid variable names.
+func checkOutputVarsPrefix(prefix string) error {
+	if prefix != "" && !variableName.MatchString(prefix) {
+		return fmt.Errorf("invalid output_vars_prefix %q: use letters, digits and underscores", prefix)
+	}
+	return nil
+}
+
+// writeOutputVars keeps the variables allowed by output_vars, prefixes them
+// with output_vars_prefix and writes them for the next steps.
+func writeOutputVars(config Config, vars map[string]string) error {
+	selected := map[string]string{}
+	for name, value := range vars {
+		if allowedVariable(name, config.OutputVars) {
+			selected[config.OutputVarsPrefix+name] = value
+		}
+	}
+	return writeEnvFile(selected, os.Getenv("DRONE_OUTPUT"))
+}
+
+// allowedVariable matches name against the allow-list. Entries ending in *
+// match by prefix, e.g. PIPELINE_STEPS_*. An empty list allows every variable.
+func allowedVariable(name string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, entry := range allowed {
+		entry = strings.TrimSpace(entry)
+		if pattern, ok := strings.CutSuffix(entry, "*"); ok && strings.HasPrefix(name, pattern) {
+			return true
+		}
+		if entry == name {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote quotes value for a POSIX shell. Inside single quotes only the
+// single quote itself needs escaping, by closing the quotes around an escaped one:
+//
+//	it's -> 'it'\''s'
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// sortedKeys returns the variable names in a stable order.
+func sortedKeys(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"io"
 	"path/filepath"
+	"pipeline-html-generator/internal/analysis"
 	"pipeline-html-generator/internal/models"
+	"pipeline-html-generator/internal/notify"
+	"slices"
 	"strconv"
 
 	htmlgenerator "pipeline-html-generator/internal/generators"
@@ -23,16 +26,92 @@ import (
 
 type (
 	Config struct {
-		AccID            string   `json:"accID"`
-		OrgID            string   `json:"orgID"`
-		ProjectID        string   `json:"projectID"`
-		PipelineID       string   `json:"pipelineID"`
-		StatusList       []string `json:"statusList"`
-		RepoName         string   `json:"repoName"`
-		Branch           string   `json:"branch"`
-		ServiceName      string   `json:"serviceName"`
-		HarnessSecret    string   `json:"harnessSecret"`
-		PipeExecutionURL string   `json:"harnessPipeExecutionURL"`
+		AccID              string   `json:"accID"`
+		OrgID              string   `json:"orgID"`
+		ProjectID          string   `json:"projectID"`
+		PipelineID         string   `json:"pipelineID"`
+		StatusList         []string `json:"statusList"`
+		RepoName           string   `json:"repoName"`
+		Branch             string   `json:"branch"`
+		ServiceName        string   `json:"serviceName"`
+		HarnessSecret      string   `json:"harnessSecret"`
+		PipeExecutionURL   string   `json:"harnessPipeExecutionURL"`
+		TemplatePath       string   `json:"templatePath"`
+		TemplateDir        string   `json:"templateDir"`
+		Theme              string   `json:"theme"`
+		ThemeFile          string   `json:"themeFile"`
+		HTMLMode           string   `json:"htmlMode"`
+		BadgeLabel         string   `json:"badgeLabel"`
+		BadgeText          string   `json:"badgeText"`
+		BadgeDuration      bool     `json:"badgeDuration"`
+		Outputs            []string `json:"outputs"`
+		OutputDir          string   `json:"outputDir"`
+		Compare            bool     `json:"compare"`
+		CompareStatus      []string `json:"compareStatus"`
+		CompareDepth       int      `json:"compareDepth"`
+		Flaky              bool     `json:"flaky"`
+		FlakyDepth         int      `json:"flakyDepth"`
+		FlakyMinScore      float64  `json:"flakyMinScore"`
+		Trend              bool     `json:"trend"`
+		TrendDepth         int      `json:"trendDepth"`
+		DoraWindowDays     int      `json:"doraWindowDays"`
+		DoraPageSize       int      `json:"doraPageSize"`
+		BudgetsFile        string   `json:"budgetsFile"`
+		BudgetFail         bool     `json:"budgetFail"`
+		SlackWebhook       string   `json:"slackWebhook"`
+		TeamsWebhook       string   `json:"teamsWebhook"`
+		SMTPHost           string   `json:"smtpHost"`
+		SMTPPort           int      `json:"smtpPort"`
+		SMTPUsername       string   `json:"smtpUsername"`
+		SMTPPassword       string   `json:"smtpPassword"`
+		SMTPSecurity       string   `json:"smtpSecurity"`
+		SMTPFrom           string   `json:"smtpFrom"`
+		SMTPTo             []string `json:"smtpTo"`
+		SMTPSubject        string   `json:"smtpSubject"`
+		SMTPAttachHTML     bool     `json:"smtpAttachHTML"`
+		S3Bucket           string   `json:"s3Bucket"`
+		S3Endpoint         string   `json:"s3Endpoint"`
+		S3Region           string   `json:"s3Region"`
+		S3AccessKey        string   `json:"s3AccessKey"`
+		S3SecretKey        string   `json:"s3SecretKey"`
+		S3SessionToken     string   `json:"s3SessionToken"`
+		S3PathStyle        bool     `json:"s3PathStyle"`
+		S3Key              string   `json:"s3Key"`
+		S3Presign          bool     `json:"s3Presign"`
+		S3PresignExpiry    string   `json:"s3PresignExpiry"`
+		CommitSha          string   `json:"commitSha"`
+		PullRequest        int      `json:"pullRequest"`
+		StatusContext      string   `json:"statusContext"`
+		GitHubToken        string   `json:"githubToken"`
+		GitHubAPIURL       string   `json:"githubAPIURL"`
+		GitHubRepo         string   `json:"githubRepo"`
+		GitHubStatus       bool     `json:"githubStatus"`
+		GitHubComment      bool     `json:"githubComment"`
+		BitbucketURL       string   `json:"bitbucketURL"`
+		BitbucketUsername  string   `json:"bitbucketUsername"`
+		BitbucketToken     string   `json:"bitbucketToken"`
+		BitbucketWorkspace string   `json:"bitbucketWorkspace"`
+		BitbucketRepo      string   `json:"bitbucketRepo"`
+		BitbucketStatus    bool     `json:"bitbucketStatus"`
+		BitbucketComment   bool     `json:"bitbucketComment"`
+		GitLabURL          string   `json:"gitlabURL"`
+		GitLabToken        string   `json:"gitlabToken"`
+		GitLabProject      string   `json:"gitlabProject"`
+		GitLabStatus       bool     `json:"gitlabStatus"`
+		GitLabComment      bool     `json:"gitlabComment"`
+		JiraURL            string   `json:"jiraURL"`
+		JiraUsername       string   `json:"jiraUsername"`
+		JiraToken          string   `json:"jiraToken"`
+		JiraProjects       []string `json:"jiraProjects"`
+		JiraTransition     string   `json:"jiraTransition"`
+		JiraEnvironment    string   `json:"jiraEnvironment"`
+		JiraCommitRange    string   `json:"jiraCommitRange"`
+		OutputVars         []string `json:"outputVars"`
+		OutputVarsPrefix   string   `json:"outputVarsPrefix"`
+		HTMLReportEncoding string   `json:"htmlReportEncoding"`
+		HTMLReportMaxSize  int      `json:"htmlReportMaxSize"`
+		NotifyRetries      int      `json:"notifyRetries"`
+		NotifyDryRun       bool     `json:"notifyDryRun"`
 	}
 
 	Plugin struct {
@@ -41,8 +120,10 @@ type (
 )
 
 type Commit struct {
-	Recast string `json:"__recast"`
-	ID     string `json:"id"`
+	Recast    string `json:"__recast"`
+	ID        string `json:"id"`
+	Message   string `json:"message"`
+	TimeStamp int64  `json:"timeStamp"` // commit time in milliseconds
 	// Include other fields if needed
 }
 
@@ -68,8 +149,18 @@ type CI struct {
 	// Include other fields if needed
 }
 
+type InfrastructureSummary struct {
+	EnvIdentifier string `json:"envIdentifier"`
+	EnvName       string `json:"envName"`
+}
+
+type CD struct {
+	InfrastructureExecutionSummaries []InfrastructureSummary `json:"infrastructureExecutionSummaries"`
+}
+
 type ModuleInfo struct {
 	CI CI `json:"ci"`
+	CD CD `json:"cd"`
 	// Include other fields if needed
 }
 
@@ -141,8 +232,28 @@ var plugin Plugin
 const lineBreak = "|---------------------------------------------"
 
 func getExecutionDetails(accID string, orgID string, projectID string, pipelineID string, statusList []string, repoName string, branch string, serviceName string) (models.Pipeline, error) {
+	executions, err := getExecutionSummaries(accID, orgID, projectID, pipelineID, statusList, repoName, branch, 1)
+	if err != nil {
+		return models.Pipeline{}, err
+	}
+	if len(executions) == 0 {
+		return models.Pipeline{}, errors.New("no successful execution found")
+	}
 
-	url := "https://app.harness.io/pipeline/api/pipelines/execution/summary?page=0&size=1&accountIdentifier=" + accID + "&orgIdentifier=" + orgID + "&projectIdentifier=" + projectID + "&pipelineIdentifier=" + pipelineID + ""
+	return buildPipeline(accID, orgID, projectID, pipelineID, executions[0])
+}
+
+// getExecutionSummaries returns up to size executions of the pipeline matching
+// the status list, branch and repository, most recent first.
+func getExecutionSummaries(accID string, orgID string, projectID string, pipelineID string, statusList []string, repoName string, branch string, size int) ([]Content, error) {
+	return getExecutionSummariesPage(accID, orgID, projectID, pipelineID, statusList, repoName, branch, 0, size)
+}
+
+// getExecutionSummariesPage returns the given page of executions, most recent
+// first. The CI filter is left out when both repoName and branch are empty.
+func getExecutionSummariesPage(accID string, orgID string, projectID string, pipelineID string, statusList []string, repoName string, branch string, page int, size int) ([]Content, error) {
+
+	url := "https://app.harness.io/pipeline/api/pipelines/execution/summary?page=" + strconv.Itoa(page) + "&size=" + strconv.Itoa(size) + "&accountIdentifier=" + accID + "&orgIdentifier=" + orgID + "&projectIdentifier=" + projectID + "&pipelineIdentifier=" + pipelineID + ""
 	method := "POST"
 
 	fmt.Println("Fetching Pipeline Execution Details on URL: ", url)
@@ -150,11 +261,15 @@ func getExecutionDetails(accID string, orgID string, projectID string, pipelineI
 	var statusListJson string
 	statusListJsonBytes, err := json.Marshal(statusList)
 	if err != nil {
-		return models.Pipeline{}, err
+		return nil, err
 	}
 	statusListJson = string(statusListJsonBytes)
 
-	payload := strings.NewReader(fmt.Sprintf(`{"status":%s,"moduleProperties":{"ci":{"branch":"%s","repoName":"%s"}},"filterType":"PipelineExecution"}`, statusListJson, branch, repoName))
+	moduleProperties := ""
+	if repoName != "" || branch != "" {
+		moduleProperties = fmt.Sprintf(`"moduleProperties":{"ci":{"branch":"%s","repoName":"%s"}},`, branch, repoName)
+	}
+	payload := strings.NewReader(fmt.Sprintf(`{"status":%s,%s"filterType":"PipelineExecution"}`, statusListJson, moduleProperties))
 
 	fmt.Println("Body: ", payload)
 	client := &http.Client{}
@@ -165,7 +280,7 @@ func getExecutionDetails(accID string, orgID string, projectID string, pipelineI
 		fmt.Println("URL: ", url)
 		fmt.Println("Payload: ", payload)
 		fmt.Println("Error: ", err)
-		return models.Pipeline{}, err
+		return nil, err
 	}
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("x-api-key", plugin.Config.HarnessSecret)
@@ -177,7 +292,7 @@ func getExecutionDetails(accID string, orgID string, projectID string, pipelineI
 		fmt.Println("Payload: ", payload)
 		fmt.Println("Error: ", err)
 		fmt.Println("Status: ", req.Response.Status)
-		return models.Pipeline{}, err
+		return nil, err
 	}
 
 	body, err := io.ReadAll(res.Body)
@@ -188,7 +303,7 @@ func getExecutionDetails(accID string, orgID string, projectID string, pipelineI
 		fmt.Println("Response: ", body)
 		fmt.Println("Error: ", err)
 		fmt.Println("Status: ", req.Response.Status)
-		return models.Pipeline{}, err
+		return nil, err
 	}
 	fmt.Println("URL: ", url)
 	fmt.Println("Payload: ", payload)
@@ -199,228 +314,307 @@ func getExecutionDetails(accID string, orgID string, projectID string, pipelineI
 	var response Response
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return models.Pipeline{}, errors.New("error parsing JSON response from Harness API Pipeline Executions")
+		return nil, errors.New("error parsing JSON response from Harness API Pipeline Executions")
 	}
 
-	if len(response.Data.Content) == 0 {
-		return models.Pipeline{}, errors.New("no successful execution found")
+	return response.Data.Content, nil
+}
+
+// buildPipeline turns an execution summary into the pipeline model, fetching
+// the execution graph of every stage to collect its steps.
+func buildPipeline(accID string, orgID string, projectID string, pipelineID string, content Content) (models.Pipeline, error) {
+	var pipeline models.Pipeline
+	fmt.Printf("| Found execution with status:\033[0m \033[1;32m%s\033[0m\n", content.Status)
+	fmt.Println(lineBreak)
+	fmt.Printf("| \033[1;36mPlan Execution ID:\033[0m \033[1;32m%s\033[0m\n", content.PlanExecutionId)
+	fmt.Printf("| \033[1;36mPipeline Name:\033[0m \033[1;32m%s\033[0m\n", content.Name)
+	fmt.Printf("| \033[1;36mPipe Status:\033[0m \033[1;32m%s\033[0m\n", content.Status)
+	fmt.Println(lineBreak)
+
+	if content.Status == "Running" {
+		content.EndTs = int(time.Now().UnixNano() / int64(time.Millisecond))
 	}
 
-	// responseBytes, err := json.Marshal(response)
-	// if err != nil {
-	// 	return models.Pipeline{}, err
-	// }
-	// pipelineParsed, err := parsePipeline(responseBytes)
-	// if err != nil {
-	// 	return models.Pipeline{}, err
-	// }
+	pipeline = models.Pipeline{
+		Name:        content.Name,
+		Status:      content.Status,
+		StartedTime: time.Unix(int64(content.StartTs/1000), 0).String(),
+		Duration:    time.Unix(int64(content.EndTs/1000), 0).Sub(time.Unix(int64(content.StartTs/1000), 0)).String(),
+		StageCount:  0,
+		StepCount:   0,
+		Message:     "",
+		ExecutionId: content.PlanExecutionId,
+		StartMs:     int64(content.StartTs),
+		EndMs:       int64(content.EndTs),
+		TriggerType: content.ExecutionTriggerInfo.TriggerType,
+		TriggeredBy: content.ExecutionTriggerInfo.TriggeredBy.Identifier,
+	}
+	if content.ExecutionTriggerInfo.TriggeredBy.ExtraInfo.Email != "" {
+		pipeline.TriggeredBy = content.ExecutionTriggerInfo.TriggeredBy.ExtraInfo.Email
+	}
 
-	// fmt.Println("Pipeline: ", pipelineParsed)
-	// fmt.Println("Pipeline Content: ", response.Data.Content)
+	// content := response.Data.Content[0]
+	// foundSuccessfulExecution := false
+
+	pipeline.Graph = models.ExecutionGraph{RootID: content.StartingNodeId, Nodes: map[string]models.GraphNode{}}
+
+	for nodeID, nodeInfo := range content.LayoutNodeMap {
+		pipeline.Graph.Nodes[nodeID] = models.GraphNode{
+			ID:       nodeID,
+			Name:     nodeInfo.Name,
+			Type:     nodeInfo.NodeType,
+			Status:   nodeInfo.Status,
+			StartMs:  int64(nodeInfo.StartTs),
+			EndMs:    graphEndMs(int64(nodeInfo.StartTs), int64(nodeInfo.EndTs)),
+			Children: nodeInfo.EdgeLayoutList.CurrentNodeChildren,
+			Next:     nodeInfo.EdgeLayoutList.NextIds,
+		}
 
-	var pipeline models.Pipeline
-	for _, content := range response.Data.Content {
-		fmt.Printf("| Found execution with status:\033[0m \033[1;32m%s\033[0m\n", content.Status)
 		fmt.Println(lineBreak)
-		fmt.Printf("| \033[1;36mPlan Execution ID:\033[0m \033[1;32m%s\033[0m\n", content.PlanExecutionId)
-		fmt.Printf("| \033[1;36mPipeline Name:\033[0m \033[1;32m%s\033[0m\n", content.Name)
-		fmt.Printf("| \033[1;36mPipe Status:\033[0m \033[1;32m%s\033[0m\n", content.Status)
+		fmt.Printf("| \033[1;36mNode Type:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeType)
+		fmt.Printf("| \033[1;36mNode Group:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeGroup)
+		fmt.Printf("| \033[1;36mNode Identifier:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeIdentifier)
+		fmt.Printf("| \033[1;36mName:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Name)
+		fmt.Printf("| \033[1;36mNode UUID:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeUuid)
+		fmt.Printf("| \033[1;36mStatus:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Status)
+		fmt.Printf("| \033[1;36mModule:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Module)
+		fmt.Printf("| \033[1;36mStart TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(nodeInfo.StartTs/1000), 0))
+		fmt.Printf("| \033[1;36mEnd TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(nodeInfo.EndTs/1000), 0))
+		fmt.Printf("| \033[1;36mFailure Info:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.FailureInfo.Message)
+		fmt.Printf("| \033[1;36mEdge Layout List:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.EdgeLayoutList)
 		fmt.Println(lineBreak)
+		stageNodeID := nodeInfo.NodeUuid
+		urlSteps := "https://app.harness.io/gateway/pipeline/api/pipelines/execution/v2/" + content.PlanExecutionId + "?page=0&size=1&accountIdentifier=" + accID + "&orgIdentifier=" + orgID + "&projectIdentifier=" + projectID + "&pipelineIdentifier=" + pipelineID + "&stageNodeId=" + stageNodeID + ""
+		methodSteps := "GET"
+		clientSteps := &http.Client{}
+		reqSteps, err := http.NewRequest(methodSteps, urlSteps, nil)
 
-		if content.Status == "Running" {
-			content.EndTs = int(time.Now().UnixNano() / int64(time.Millisecond))
+		if err != nil {
+			return models.Pipeline{}, err
 		}
+		reqSteps.Header.Add("Content-Type", "application/json")
+		reqSteps.Header.Add("x-api-key", plugin.Config.HarnessSecret)
 
-		pipeline = models.Pipeline{
-			Name:        content.Name,
-			Status:      content.Status,
-			StartedTime: time.Unix(int64(content.StartTs/1000), 0).String(),
-			Duration:    time.Unix(int64(content.EndTs/1000), 0).Sub(time.Unix(int64(content.StartTs/1000), 0)).String(),
-			StageCount:  0,
-			StepCount:   0,
-			Message:     "",
-			ExecutionId: content.PlanExecutionId,
+		resSteps, err := clientSteps.Do(reqSteps)
+		if err != nil {
+			return models.Pipeline{}, err
 		}
 
-		// content := response.Data.Content[0]
-		// foundSuccessfulExecution := false
-
-		for _, nodeInfo := range content.LayoutNodeMap {
-			fmt.Println(lineBreak)
-			fmt.Printf("| \033[1;36mNode Type:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeType)
-			fmt.Printf("| \033[1;36mNode Group:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeGroup)
-			fmt.Printf("| \033[1;36mNode Identifier:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeIdentifier)
-			fmt.Printf("| \033[1;36mName:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Name)
-			fmt.Printf("| \033[1;36mNode UUID:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.NodeUuid)
-			fmt.Printf("| \033[1;36mStatus:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Status)
-			fmt.Printf("| \033[1;36mModule:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.Module)
-			fmt.Printf("| \033[1;36mStart TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(nodeInfo.StartTs/1000), 0))
-			fmt.Printf("| \033[1;36mEnd TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(nodeInfo.EndTs/1000), 0))
-			fmt.Printf("| \033[1;36mFailure Info:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.FailureInfo.Message)
-			fmt.Printf("| \033[1;36mEdge Layout List:\033[0m \033[1;32m%s\033[0m\n", nodeInfo.EdgeLayoutList)
-			fmt.Println(lineBreak)
-			stageNodeID := nodeInfo.NodeUuid
-			urlSteps := "https://app.harness.io/gateway/pipeline/api/pipelines/execution/v2/" + content.PlanExecutionId + "?page=0&size=1&accountIdentifier=" + accID + "&orgIdentifier=" + orgID + "&projectIdentifier=" + projectID + "&pipelineIdentifier=" + pipelineID + "&stageNodeId=" + stageNodeID + ""
-			methodSteps := "GET"
-			clientSteps := &http.Client{}
-			reqSteps, err := http.NewRequest(methodSteps, urlSteps, nil)
-
-			if err != nil {
-				return models.Pipeline{}, err
-			}
-			reqSteps.Header.Add("Content-Type", "application/json")
-			reqSteps.Header.Add("x-api-key", plugin.Config.HarnessSecret)
+		bodySteps, err := io.ReadAll(resSteps.Body)
 
-			resSteps, err := clientSteps.Do(reqSteps)
-			if err != nil {
-				return models.Pipeline{}, err
-			}
+		if err != nil {
+			return models.Pipeline{}, err
+		}
 
-			bodySteps, err := io.ReadAll(resSteps.Body)
+		fmt.Printf("Response Execution Details for stage %s: \n%s", nodeInfo.Name, bodySteps)
 
+		// fmt.Printf("| \033[1;36mResponse Body:\033[0m \033[1;32m%s\033[0m\n", string(bodySteps))
+		defer resSteps.Body.Close()
 
-			if err != nil {
-				return models.Pipeline{}, err
+		var payloadSteps models.PayloadSteps
+		err = json.Unmarshal(bodySteps, &payloadSteps)
+		if err != nil {
+			return models.Pipeline{}, errors.New("error parsing JSON Stage Details response from Harness API Pipeline Executions")
+		}
+		// also check if number of step is less than 1 (payloadSteps.Data.ExecutionGraph.NodeMap[])
+		if isReportedStage(nodeInfo) {
+
+			var startTS string
+			var endTS string
+			var duration string
+
+			if nodeInfo.Status == "Skipped" {
+				startTS = ""
+				endTS = ""
+				duration = "0s"
+			} else if nodeInfo.Status == "Running" || nodeInfo.Status == "AsyncWaiting" {
+				nodeInfo.EndTs = int(time.Now().UnixNano() / int64(time.Millisecond))
+				startTS = time.Unix(int64(nodeInfo.StartTs/1000), 0).String()
+				endTS = time.Unix(int64(nodeInfo.EndTs/1000), 0).String()
+				// use now as end time
+				duration = time.Unix(int64(time.Now().UnixNano()/1000), 0).Sub(time.Unix(int64(nodeInfo.StartTs/1000), 0)).String()
+			} else {
+				startTS = time.Unix(int64(nodeInfo.StartTs/1000), 0).String()
+				endTS = time.Unix(int64(nodeInfo.EndTs/1000), 0).String()
+				duration = time.Unix(int64(nodeInfo.EndTs/1000), 0).Sub(time.Unix(int64(nodeInfo.StartTs/1000), 0)).String()
 			}
-			
-			fmt.Printf("Response Execution Details for stage %s: \n%s",nodeInfo.Name,bodySteps)
-
-			// fmt.Printf("| \033[1;36mResponse Body:\033[0m \033[1;32m%s\033[0m\n", string(bodySteps))
-			defer resSteps.Body.Close()
 
-			var payloadSteps models.PayloadSteps
-			err = json.Unmarshal(bodySteps, &payloadSteps)
-			if err != nil {
-				return models.Pipeline{}, errors.New("error parsing JSON Stage Details response from Harness API Pipeline Executions")
-			}
-			// also check if number of step is less than 1 (payloadSteps.Data.ExecutionGraph.NodeMap[])
-			if nodeInfo.Name != "" && nodeInfo.NodeType != "STEP_GROUP" && nodeInfo.NodeType != "NG_FORK" && nodeInfo.NodeType != "ROLLBACK_OPTIONAL_CHILD_CHAIN" && nodeInfo.Status != "NotStarted" && nodeInfo.Status != "Skipped" {
-
-				var startTS string
-				var endTS string
-				var duration string
-
-				if nodeInfo.Status == "Skipped" {
-					startTS = ""
-					endTS = ""
-					duration = "0s"
-				} else if nodeInfo.Status == "Running" || nodeInfo.Status == "AsyncWaiting" {
-					nodeInfo.EndTs = int(time.Now().UnixNano() / int64(time.Millisecond))
-					startTS = time.Unix(int64(nodeInfo.StartTs/1000), 0).String()
-					endTS = time.Unix(int64(nodeInfo.EndTs/1000), 0).String()
-					// use now as end time
-					duration = time.Unix(int64(time.Now().UnixNano()/1000), 0).Sub(time.Unix(int64(nodeInfo.StartTs/1000), 0)).String()
-				} else {
-					startTS = time.Unix(int64(nodeInfo.StartTs/1000), 0).String()
-					endTS = time.Unix(int64(nodeInfo.EndTs/1000), 0).String()
-					duration = time.Unix(int64(nodeInfo.EndTs/1000), 0).Sub(time.Unix(int64(nodeInfo.StartTs/1000), 0)).String()
+			pipeline.Stages = append(pipeline.Stages, models.Stage{
+				ID:       nodeID,
+				Name:     nodeInfo.Name,
+				Status:   nodeInfo.Status,
+				Module:   nodeInfo.Module,
+				Steps:    []models.Step{},
+				StartTs:  startTS,
+				EndTs:    endTS,
+				Duration: duration,
+				StartMs:  int64(nodeInfo.StartTs),
+				EndMs:    int64(nodeInfo.EndTs),
+				Graph:    stepGraph(payloadSteps),
+			})
+
+			for stepID, node := range payloadSteps.Data.ExecutionGraph.NodeMap {
+				fmt.Println(lineBreak)
+				fmt.Printf("| \033[1;36mStep Name:\033[0m \033[1;32m%s\033[0m\n", node.Name)
+				fmt.Printf("| \033[1;36mStep Identifier:\033[0m \033[1;32m%s\033[0m\n", node.Identifier)
+				if node.Status != "Skipped" {
+					fmt.Printf("| \033[1;36mStep Start TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.StartTs/1000), 0))
+					fmt.Printf("| \033[1;36mStep End TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.EndTs/1000), 0))
+					fmt.Printf("| \033[1;36mStep Duration:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.EndTs/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)))
 				}
-
-				pipeline.Stages = append(pipeline.Stages, models.Stage{
-					Name:     nodeInfo.Name,
-					Status:   nodeInfo.Status,
-					Module:   nodeInfo.Module,
-					Steps:    []models.Step{},
-					StartTs:  startTS,
-					EndTs:    endTS,
-					Duration: duration,
-				})
-
-				for _, node := range payloadSteps.Data.ExecutionGraph.NodeMap {
-					fmt.Println(lineBreak)
-					fmt.Printf("| \033[1;36mStep Name:\033[0m \033[1;32m%s\033[0m\n", node.Name)
-					fmt.Printf("| \033[1;36mStep Identifier:\033[0m \033[1;32m%s\033[0m\n", node.Identifier)
-					if node.Status != "Skipped" {
-						fmt.Printf("| \033[1;36mStep Start TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.StartTs/1000), 0))
-						fmt.Printf("| \033[1;36mStep End TS:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.EndTs/1000), 0))
-						fmt.Printf("| \033[1;36mStep Duration:\033[0m \033[1;32m%s\033[0m\n", time.Unix(int64(node.EndTs/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)))
-					}
-					fmt.Printf("| \033[1;36mStep Status:\033[0m \033[1;32m%s\033[0m\n", node.Status)
-					fmt.Printf("| \033[1;36mStep Type:\033[0m \033[1;32m%s\033[0m\n", node.StepType)
-					if node.FailureInfo.Message != "" {
-						fmt.Printf("| \033[1;36mStep Failure Info:\033[0m \033[1;32m%s\033[0m\n", node.FailureInfo.Message)
-						fmt.Printf("| \033[1;36mStep Failure Type List:\033[0m \033[1;32m%s\033[0m\n", node.FailureInfo.FailureTypeList)
-					}
-					fmt.Println(lineBreak)
-					if node.Identifier != "execution" && node.Name != "parallel" && node.Name != "liteEngineTask" && node.StepType != "STEP_GROUP" && node.StepType != "NG_FORK" && node.StepType != "ROLLBACK_OPTIONAL_CHILD_CHAIN" && node.StepType != "IntegrationStageStepPMS" && node.Status != "NotStarted" && node.Status != "Skipped" {
-						var startTS string
-						var endTS string
-						var duration string
-						var message string
-						var status string
-						if node.Status == "Skipped" {
-							startTS = ""
-							endTS = ""
-							duration = "0s"
+				fmt.Printf("| \033[1;36mStep Status:\033[0m \033[1;32m%s\033[0m\n", node.Status)
+				fmt.Printf("| \033[1;36mStep Type:\033[0m \033[1;32m%s\033[0m\n", node.StepType)
+				if node.FailureInfo.Message != "" {
+					fmt.Printf("| \033[1;36mStep Failure Info:\033[0m \033[1;32m%s\033[0m\n", node.FailureInfo.Message)
+					fmt.Printf("| \033[1;36mStep Failure Type List:\033[0m \033[1;32m%s\033[0m\n", node.FailureInfo.FailureTypeList)
+				}
+				fmt.Println(lineBreak)
+				if isReportedStep(node) {
+					var startTS string
+					var endTS string
+					var duration string
+					var message string
+					var status string
+					if node.Status == "Skipped" {
+						startTS = ""
+						endTS = ""
+						duration = "0s"
+					} else {
+						startTS = time.Unix(int64(node.StartTs/1000), 0).String()
+						if node.Status == "Running" || node.Status == "AsyncWaiting" {
+							endTS = time.Unix(0, time.Now().UnixNano()).String()
+							node.EndTs = time.Now().UnixNano() / int64(time.Millisecond)
+							duration = time.Unix(int64(time.Now().UnixNano()/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)).String()
 						} else {
-							startTS = time.Unix(int64(node.StartTs/1000), 0).String()
-							if node.Status == "Running" || node.Status == "AsyncWaiting" {
-								endTS = time.Unix(0, time.Now().UnixNano()).String()
-								node.EndTs = time.Now().UnixNano() / int64(time.Millisecond)
-								duration = time.Unix(int64(time.Now().UnixNano()/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)).String()
-							} else {
-								endTS = time.Unix(int64(node.EndTs/1000), 0).String()
-								duration = time.Unix(int64(node.EndTs/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)).String()
-							}
-
+							endTS = time.Unix(int64(node.EndTs/1000), 0).String()
+							duration = time.Unix(int64(node.EndTs/1000), 0).Sub(time.Unix(int64(node.StartTs/1000), 0)).String()
 						}
 
-						if node.Status != "Success" && node.FailureInfo.Message != "" {
-							message = node.FailureInfo.Message
-							status = node.Status
-						} else if node.Status == "Success" && node.FailureInfo.Message != "" {
-							message = "Ignored Error"
-							// status = "Success - Error Ignored"
-						} else {
-							message = node.FailureInfo.Message
-							status = node.Status
-						}
+					}
 
-						pipeline.Stages[pipeline.StageCount].Steps = append(pipeline.Stages[pipeline.StageCount].Steps, models.Step{
-							Name:        node.Name,
-							Status:      status,
-							Message:     message,
-							StartTs:     startTS,
-							EndTs:       endTS,
-							Duration:    duration,
-							FailureInfo: node.FailureInfo,
-						})
-						pipeline.StepCount++
+					if node.Status != "Success" && node.FailureInfo.Message != "" {
+						message = node.FailureInfo.Message
+						status = node.Status
+					} else if node.Status == "Success" && node.FailureInfo.Message != "" {
+						message = "Ignored Error"
+						// status = "Success - Error Ignored"
+					} else {
+						message = node.FailureInfo.Message
+						status = node.Status
 					}
-				}
 
-				pipeline.StageCount++
+					pipeline.Stages[pipeline.StageCount].Steps = append(pipeline.Stages[pipeline.StageCount].Steps, models.Step{
+						ID:          stepID,
+						Name:        node.Name,
+						Status:      status,
+						Message:     message,
+						StartTs:     startTS,
+						EndTs:       endTS,
+						Duration:    duration,
+						StartMs:     node.StartTs,
+						EndMs:       node.EndTs,
+						FailureInfo: node.FailureInfo,
+						StepType:    node.StepType,
+						Interrupts:  node.InterruptHistories,
+					})
+					pipeline.StepCount++
+				}
 			}
+
+			pipeline.StageCount++
 		}
+	}
 
-		// fmt.Printf("| \033[1;36mStage ID:\033[0m \033[1;32m%s\033[0m\n", stageID)
+	// fmt.Printf("| \033[1;36mStage ID:\033[0m \033[1;32m%s\033[0m\n", stageID)
 
-		var commiters []string
+	var commiters []string
 
-		for _, commit := range content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits {
-			commiters = append(commiters, commit.ID)
-		}
-		// fmt.Printf("Commits: %s\n", strings.Join(commiters, ", "))
-		fmt.Printf("| \033[1;36mNumber of commits:\033[0m \033[1;32m%d\033[0m\n", len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits))
-		fmt.Println(lineBreak)
-		// fmt.Printf("Last Commit SHA: %s\n", content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits[len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits)-1].ID)
+	for _, commit := range content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits {
+		commiters = append(commiters, commit.ID)
+	}
+	// fmt.Printf("Commits: %s\n", strings.Join(commiters, ", "))
+	fmt.Printf("| \033[1;36mNumber of commits:\033[0m \033[1;32m%d\033[0m\n", len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits))
+	fmt.Println(lineBreak)
+	// fmt.Printf("Last Commit SHA: %s\n", content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits[len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits)-1].ID)
 
-		if len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits) > 0 {
-			// fmt.Printf("First Commit SHA: %s\n", content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits[0].ID)
-		} else {
-			fmt.Println("No commits found")
+	if len(content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits) > 0 {
+		// fmt.Printf("First Commit SHA: %s\n", content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits[0].ID)
+		pipeline.CommitSha = content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits[0].ID
+	} else {
+		fmt.Println("No commits found")
+	}
+	for _, commit := range content.ModuleInfo.CI.CIExecutionInfoDTO.Branch.Commits {
+		pipeline.Commits = append(pipeline.Commits, models.Commit{Sha: commit.ID, Message: commit.Message, TimestampMs: commit.TimeStamp})
+	}
+	for _, infrastructure := range content.ModuleInfo.CD.InfrastructureExecutionSummaries {
+		environment := infrastructure.EnvName
+		if environment == "" {
+			environment = infrastructure.EnvIdentifier
+		}
+		if environment != "" && !slices.Contains(pipeline.Environments, environment) {
+			pipeline.Environments = append(pipeline.Environments, environment)
 		}
+	}
+
+	analysis.SplitWaitTime(&pipeline)
+
+	return pipeline, nil
+}
+
+// isReportedStage reports whether a node of the pipeline layout is listed as a
+// stage. Parallel wrappers, step groups, rollback chains and stages that never
+// ran are left out.
+func isReportedStage(nodeInfo NodeInfo) bool {
+	return nodeInfo.Name != "" && nodeInfo.NodeType != "STEP_GROUP" && nodeInfo.NodeType != "NG_FORK" && nodeInfo.NodeType != "ROLLBACK_OPTIONAL_CHILD_CHAIN" && nodeInfo.Status != "NotStarted" && nodeInfo.Status != "Skipped"
+}
 
-		return pipeline, nil
+// isReportedStep reports whether a node of the step graph is listed as a step.
+// Wrappers (execution, parallel, step groups), the CI setup and steps that
+// never ran are left out.
+func isReportedStep(node models.Node) bool {
+	return node.Identifier != "execution" && node.Name != "parallel" && node.Name != "liteEngineTask" && node.StepType != "STEP_GROUP" && node.StepType != "NG_FORK" && node.StepType != "ROLLBACK_OPTIONAL_CHILD_CHAIN" && node.StepType != "IntegrationStageStepPMS" && node.Status != "NotStarted" && node.Status != "Skipped"
+}
+
+// stepGraph converts the execution graph of a stage into the model used for critical path analysis.
+func stepGraph(payloadSteps models.PayloadSteps) models.ExecutionGraph {
+	executionGraph := payloadSteps.Data.ExecutionGraph
+	graph := models.ExecutionGraph{RootID: executionGraph.RootNodeId, Nodes: map[string]models.GraphNode{}}
+	for nodeID, node := range executionGraph.NodeMap {
+		edges := executionGraph.NodeAdjacencyListMap[nodeID]
+		graph.Nodes[nodeID] = models.GraphNode{
+			ID:       nodeID,
+			Name:     node.Name,
+			Type:     node.StepType,
+			Status:   node.Status,
+			StartMs:  node.StartTs,
+			EndMs:    graphEndMs(node.StartTs, node.EndTs),
+			Children: edges.Children,
+			Next:     edges.NextIds,
+			Hidden:   !isReportedStep(node),
+		}
 	}
+	return graph
+}
 
-	return models.Pipeline{}, errors.New("no successful execution found")
+// graphEndMs uses the current time as the end of nodes that are still running.
+func graphEndMs(startMs int64, endMs int64) int64 {
+	if startMs > 0 && endMs == 0 {
+		return time.Now().UnixMilli()
+	}
+	return endMs
 }
 
 func (p *Plugin) Exec() error {
 
 	plugin = *p
 
+	if err := checkOutputVarsPrefix(p.Config.OutputVarsPrefix); err != nil {
+		return err
+	}
+	if err := checkHTMLReportEncoding(p.Config.HTMLReportEncoding); err != nil {
+		return err
+	}
+
 	var accID string = p.Config.AccID
 	var orgID string = p.Config.OrgID
 	var projectID string = p.Config.ProjectID
@@ -485,35 +679,159 @@ func (p *Plugin) Exec() error {
 
 	fmt.Println(lineBreak)
 	// Create USer Execution Link
-	executionLink := "https://app.harness.io/ng/account/" + accID + "/ci/orgs/" + orgID + "/projects/" + projectID + "/pipelines/" + pipelineID + "/deployments/" + pipeline.ExecutionId + "/pipeline"
-	pipeline.ExecutionLink = executionLink
-	dashHTML, err := htmlgenerator.GenerateDashboardHTML(pipeline)
+	pipeline.ExecutionLink = buildExecutionLink(p.Config, pipeline.ExecutionId)
+
+	if p.Config.Compare {
+		previous, err := getPreviousExecution(p.Config, pipeline, p.Config.CompareStatus, p.Config.CompareDepth)
+		if err != nil {
+			fmt.Println("| \033[33m[WARNING] - Error getting previous execution to compare: ", err, "\033[0m")
+		} else {
+			comparison := analysis.Compare(pipeline, previous)
+			pipeline.Comparison = &comparison
+			fmt.Printf("| \033[1;36mCompared with execution:\033[0m \033[1;32m%s\033[0m\n", previous.ExecutionId)
+		}
+		fmt.Println(lineBreak)
+	}
+
+	if p.Config.Flaky || requestsFormat(p.Config.Outputs, "flaky") {
+		executions, err := getRecentExecutions(p.Config, pipeline, p.Config.FlakyDepth)
+		if err != nil {
+			fmt.Println("| \033[33m[WARNING] - Error getting recent executions for flaky step detection: ", err, "\033[0m")
+		} else {
+			pipeline.FlakySteps = analysis.DetectFlakySteps(executions, p.Config.FlakyMinScore)
+			fmt.Printf("| \033[1;36mFlaky steps found:\033[0m \033[1;32m%d\033[0m in %d executions\n", len(pipeline.FlakySteps), len(executions))
+		}
+		fmt.Println(lineBreak)
+	}
+
+	if p.Config.Trend {
+		trend, err := getTrend(p.Config, pipeline, p.Config.TrendDepth)
+		if err != nil {
+			fmt.Println("| \033[33m[WARNING] - Error getting execution trend: ", err, "\033[0m")
+		} else {
+			pipeline.Trend = &trend
+			fmt.Printf("| \033[1;36mSuccess rate:\033[0m \033[1;32m%.1f%%\033[0m over %d executions\n", trend.SuccessRate, len(trend.Executions))
+		}
+		fmt.Println(lineBreak)
+	}
+
+	if p.Config.BudgetsFile != "" {
+		budgets, err := analysis.LoadBudgets(p.Config.BudgetsFile)
+		if err != nil {
+			return err
+		}
+		pipeline.BudgetBreaches = analysis.CheckBudgets(pipeline, budgets)
+		for _, breach := range pipeline.BudgetBreaches {
+			fmt.Printf("| \033[31m[BUDGET] - %s: %s exceeds %s by %s\033[0m\n", budgetTarget(pipeline, breach), time.Duration(breach.ActualMs)*time.Millisecond, time.Duration(breach.BudgetMs)*time.Millisecond, time.Duration(breach.OverMs)*time.Millisecond)
+		}
+		fmt.Printf("| \033[1;36mDuration budgets exceeded:\033[0m \033[1;32m%d\033[0m\n", len(pipeline.BudgetBreaches))
+		fmt.Println(lineBreak)
+	}
+
+	theme, err := htmlgenerator.LoadTheme(p.Config.Theme, p.Config.ThemeFile)
+	if err != nil {
+		return err
+	}
+	renderOptions := htmlgenerator.Options{
+		Mode:         p.Config.HTMLMode,
+		TemplatePath: p.Config.TemplatePath,
+		TemplateDir:  p.Config.TemplateDir,
+		Theme:        theme,
+		Badge: htmlgenerator.BadgeOptions{
+			Label:        p.Config.BadgeLabel,
+			Text:         p.Config.BadgeText,
+			ShowDuration: p.Config.BadgeDuration,
+		},
+	}
+	outputs := newOutputSet(pipeline, renderOptions)
+	specs, err := parseOutputs(p.Config.Outputs, p.Config.OutputDir, pipeline)
+	if err != nil {
+		return err
+	}
+	err = writeOutputs(outputs, specs)
 	if err != nil {
 		return err
 	}
 
-	//save to a html file
-	err = os.WriteFile("pipeline.html", []byte(dashHTML), 0644)
+	htmlReportValue, htmlReportContent, err := htmlReport(outputs, p.Config.HTMLReportEncoding, p.Config.HTMLReportMaxSize)
+	if err != nil {
+		return err
+	}
+	htmlReportEncoding := p.Config.HTMLReportEncoding
+	if htmlReportContent == "none" {
+		htmlReportEncoding = ""
+	}
+	markdown, err := outputs.render("markdown")
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(lineBreak)
-	fmt.Println("| \033[1;36mPipeline HTML Generator saved to pipeline.html\033[0m")
-	fmt.Println(lineBreak)
+	sender := notify.NewSender(p.Config.NotifyRetries, p.Config.NotifyDryRun)
+	var reportURL string
+	if p.Config.S3Bucket != "" {
+		fmt.Println(lineBreak)
+		fmt.Println("| \033[1;36mUploading outputs to S3...\033[0m")
+		fmt.Println(lineBreak)
+		reportURL, err = uploadOutputs(p.Config, pipeline, outputs, specs, sender)
+		if err != nil {
+			fmt.Println("| \033[33m[WARNING] - Error uploading outputs to S3: ", err, "\033[0m")
+		} else if reportURL != "" {
+			fmt.Printf("| \033[1;36mReport URL:\033[0m \033[1;32m%s\033[0m\n", reportURL)
+		}
+		fmt.Println(lineBreak)
+	}
+
+	sendNotifications(p.Config, pipeline, outputs, sender)
+	reportToSCM(p.Config, pipeline, outputs, sender)
+	jiraIssues := reportToJira(p.Config, pipeline, sender)
+
+	failures := htmlgenerator.CollectFailures(pipeline)
+
 	// save to env file
 	vars := map[string]string{
-		"PIPELINE_NAME":        pipeline.Name,
-		"PIPELINE_STATUS":      pipeline.Status,
-		"PIPELINE_STARTEDTIME": pipeline.StartedTime,
-		"PIPELINE_DURATION":    pipeline.Duration,
-		"PIPELINE_STAGECOUNT":  strconv.Itoa(pipeline.StageCount),
-		"PIPELINE_STEPCOUNT":   strconv.Itoa(pipeline.StepCount),
-		"PIPELINE_MESSAGE":     pipeline.Message,
-		"HTML_REPORT":          strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(dashHTML, "\n", ""), "	", ""), "		", ""), "<!DOCTYPE html>", ""),
+		"PIPELINE_NAME":             pipeline.Name,
+		"PIPELINE_EXECUTION_ID":     pipeline.ExecutionId,
+		"PIPELINE_EXECUTION_URL":    pipeline.ExecutionLink,
+		"PIPELINE_TRIGGER_TYPE":     pipeline.TriggerType,
+		"PIPELINE_TRIGGERED_BY":     pipeline.TriggeredBy,
+		"PIPELINE_COMMIT_SHA":       commitSha(p.Config, pipeline),
+		"PIPELINE_DURATION_SECONDS": durationSeconds(pipeline, time.Now()),
+		"PIPELINE_FAILED_STAGES":    strings.Join(htmlgenerator.FailedStages(failures), ","),
+		"PIPELINE_STATUS":           pipeline.Status,
+		"PIPELINE_STARTEDTIME":      pipeline.StartedTime,
+		"PIPELINE_DURATION":         pipeline.Duration,
+		"PIPELINE_STAGECOUNT":       strconv.Itoa(pipeline.StageCount),
+		"PIPELINE_STEPCOUNT":        strconv.Itoa(pipeline.StepCount),
+		"PIPELINE_MESSAGE":          pipeline.Message,
+		"PIPELINE_FAILED_STEPS":     strings.Join(htmlgenerator.FailedSteps(failures), ","),
+		"PIPELINE_FAILURE_TYPES":    strings.Join(htmlgenerator.FailureTypes(failures), ","),
+		"PIPELINE_FLAKY_STEPS":      strings.Join(flakySteps(pipeline.FlakySteps), ","),
+		"PIPELINE_BUDGET_BREACHES":  strings.Join(budgetBreaches(pipeline), ","),
+		"PIPELINE_JIRA_ISSUES":      strings.Join(jiraIssues, ","),
+		"REPORT_URL":                reportURL,
+		"MARKDOWN_REPORT":           string(markdown),
+		"HTML_REPORT":               htmlReportValue,
+		"HTML_REPORT_ENCODING":      htmlReportEncoding,
+		"HTML_REPORT_CONTENT":       htmlReportContent,
 	}
 
-	err = writeEnvFile(vars, os.Getenv("DRONE_OUTPUT"))
+	var stageStatuses, stepStatuses []string
+	for _, stage := range pipeline.Stages {
+		stageStatuses = append(stageStatuses, stage.Status)
+		for _, step := range stage.Steps {
+			status := step.Status
+			if status == "" && step.FailureInfo.Message != "" {
+				// buildPipeline clears the status of steps whose error was ignored
+				status = "IgnoreFailed"
+			}
+			stepStatuses = append(stepStatuses, status)
+		}
+	}
+	addStatusCounts(vars, "PIPELINE_STAGES", stageStatuses)
+	addStatusCounts(vars, "PIPELINE_STEPS", stepStatuses)
+	addReportPaths(vars, specs)
+
+	err = writeOutputVars(p.Config, vars)
 	if err != nil {
 		// return err
 		fmt.Println("| \033[33m[WARNING] - Error writing to .env file: ", err, "\033[0m")
@@ -530,9 +848,33 @@ func (p *Plugin) Exec() error {
 	fmt.Println("| \033[1;36mPipeline HTML Generator Plugin Completed\033[0m")
 	fmt.Println(lineBreak)
 
+	if p.Config.BudgetFail && len(pipeline.BudgetBreaches) > 0 {
+		return fmt.Errorf("%d duration budgets exceeded: %s", len(pipeline.BudgetBreaches), strings.Join(budgetBreaches(pipeline), ", "))
+	}
+
 	return nil
 }
 
+// budgetTarget names the node of a breach: "Stage/Step", "Stage" or the pipeline name.
+func budgetTarget(pipeline models.Pipeline, breach models.BudgetBreach) string {
+	switch {
+	case breach.Step != "":
+		return breach.Stage + "/" + breach.Step
+	case breach.Stage != "":
+		return breach.Stage
+	}
+	return pipeline.Name
+}
+
+// budgetBreaches formats the budget breaches of a pipeline for output variables.
+func budgetBreaches(pipeline models.Pipeline) []string {
+	var names []string
+	for _, breach := range pipeline.BudgetBreaches {
+		names = append(names, budgetTarget(pipeline, breach))
+	}
+	return names
+}
+
 func writeEnvFile(vars map[string]string, outputPath string) error {
 	if outputPath == "" {
 		return writeDefaultEnvFile(vars)
@@ -549,8 +891,10 @@ func writeDefaultEnvFile(vars map[string]string) error {
 	}
 	defer f.Close()
 
-	for key, value := range vars {
-		f.WriteString(fmt.Sprintf("export %s='%s'\n", key, value))
+	for _, key := range sortedKeys(vars) {
+		if _, err := fmt.Fprintf(f, "export %s=%s\n", key, shellQuote(vars[key])); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"pipeline-html-generator/internal/models"
+	"pipeline-html-generator/internal/notify"
+)
+
+// scmReporter sets commit statuses and sticky pull request comments on a source control host.
+type scmReporter interface {
+	SetStatus(sha string, status notify.CommitStatus) error
+	UpsertComment(pullRequest int, comment notify.Comment) error
+}
+
+// scmIntegration is a configured source control host and what to report to it.
+type scmIntegration struct {
+	name     string
+	reporter scmReporter
+	status   bool
+	comment  bool
+	// marker hides the comment marker; hosts that escape HTML need a Markdown construct.
+	marker string
+}
+
+// commentMarker identifies the sticky pull request comment of a pipeline, so
+// each pipeline keeps one comment that later executions update.
+func commentMarker(config Config) string {
+	return fmt.Sprintf("<!-- pipeline-html-generator:%s -->", config.PipelineID)
+}
+
+// markdownCommentMarker is an empty Markdown link reference, for hosts that show HTML comments.
+func markdownCommentMarker(config Config) string {
+	return fmt.Sprintf("[//]: # (pipeline-html-generator:%s)\n", config.PipelineID)
+}
+
+// scmIntegrations returns the source control hosts with credentials and something to report.
+func scmIntegrations(config Config, sender *notify.Sender) []scmIntegration {
+	var integrations []scmIntegration
+	if config.GitHubToken != "" && config.GitHubRepo != "" {
+		integrations = append(integrations, scmIntegration{
+			name:     "GitHub",
+			reporter: notify.GitHub{BaseURL: config.GitHubAPIURL, Token: config.GitHubToken, Repo: config.GitHubRepo, Sender: sender},
+			status:   config.GitHubStatus,
+			comment:  config.GitHubComment,
+			marker:   commentMarker(config),
+		})
+	}
+	if config.BitbucketToken != "" && config.BitbucketRepo != "" {
+		integrations = append(integrations, scmIntegration{
+			name: "Bitbucket",
+			reporter: notify.Bitbucket{
+				BaseURL:  config.BitbucketURL,
+				Username: config.BitbucketUsername,
+				Token:    config.BitbucketToken,
+				Project:  config.BitbucketWorkspace,
+				Repo:     config.BitbucketRepo,
+				Sender:   sender,
+			},
+			status:  config.BitbucketStatus,
+			comment: config.BitbucketComment,
+			marker:  markdownCommentMarker(config),
+		})
+	}
+	if config.GitLabToken != "" && config.GitLabProject != "" {
+		integrations = append(integrations, scmIntegration{
+			name:     "GitLab",
+			reporter: notify.GitLab{BaseURL: config.GitLabURL, Token: config.GitLabToken, Project: config.GitLabProject, Sender: sender},
+			status:   config.GitLabStatus,
+			comment:  config.GitLabComment,
+			marker:   commentMarker(config),
+		})
+	}
+	return integrations
+}
+
+// commitStatus describes the execution as the build status of its commit.
+func commitStatus(config Config, pipeline models.Pipeline) notify.CommitStatus {
+	context := config.StatusContext
+	if context == "" {
+		context = "harness/" + config.PipelineID
+	}
+	description := pipeline.Status
+	if pipeline.Duration != "" {
+		description += " in " + pipeline.Duration
+	}
+	return notify.CommitStatus{
+		Outcome:     notify.Outcome(pipeline.Status),
+		Context:     context,
+		Name:        pipeline.Name,
+		Description: description,
+		URL:         pipeline.ExecutionLink,
+	}
+}
+
+// commitSha returns the commit the execution built: the configured one or the CI commit info.
+func commitSha(config Config, pipeline models.Pipeline) string {
+	if config.CommitSha != "" {
+		return config.CommitSha
+	}
+	return pipeline.CommitSha
+}
+
+// reportToSCM sets the commit status and the pull request comment on the
+// configured source control hosts. Failures are reported as warnings.
+func reportToSCM(config Config, pipeline models.Pipeline, outputs *outputSet, sender *notify.Sender) {
+	for _, integration := range scmIntegrations(config, sender) {
+		if !integration.status && !integration.comment {
+			continue
+		}
+		fmt.Println(lineBreak)
+		fmt.Printf("| \033[1;36mReporting to %s...\033[0m\n", integration.name)
+		fmt.Println(lineBreak)
+
+		if integration.status {
+			sha := commitSha(config, pipeline)
+			if sha == "" {
+				fmt.Printf("| \033[33m[WARNING] - No commit SHA to set the %s status on, set commit_sha\033[0m\n", integration.name)
+			} else if err := integration.reporter.SetStatus(sha, commitStatus(config, pipeline)); err != nil {
+				fmt.Printf("| \033[33m[WARNING] - Error setting the %s commit status: %v\033[0m\n", integration.name, err)
+			} else {
+				fmt.Printf("| \033[1;36m%s status set on\033[0m \033[1;32m%s\033[0m\n", integration.name, sha)
+			}
+		}
+
+		if integration.comment {
+			markdown, err := outputs.render("markdown")
+			switch {
+			case err != nil:
+				fmt.Println("| \033[33m[WARNING] - Error rendering the pull request comment: ", err, "\033[0m")
+			case config.PullRequest <= 0:
+				fmt.Println("| \033[33m[WARNING] - No pull request to comment on, set pull_request\033[0m")
+			default:
+				err = integration.reporter.UpsertComment(config.PullRequest, notify.Comment{Marker: integration.marker, Body: string(markdown)})
+				if err != nil {
+					fmt.Printf("| \033[33m[WARNING] - Error commenting on the %s pull request: %v\033[0m\n", integration.name, err)
+				} else {
+					fmt.Printf("| \033[1;36m%s pull request comment updated on\033[0m \033[1;32m#%d\033[0m\n", integration.name, config.PullRequest)
+				}
+			}
+		}
+		fmt.Println(lineBreak)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"pipeline-html-generator/internal/models"
+	"pipeline-html-generator/internal/notify"
+	"pipeline-html-generator/internal/storage"
+	"strings"
+	"time"
+)
+
+// defaultS3Key places the outputs of an execution next to each other, with the html output as index.html.
+const defaultS3Key = "{pipeline}/{executionId}/{file}"
+
+// contentTypes are the Content-Type of the uploaded outputs by format.
+var contentTypes = map[string]string{
+	"html":     "text/html; charset=utf-8",
+	"email":    "text/html; charset=utf-8",
+	"summary":  "text/html; charset=utf-8",
+	"flaky":    "text/html; charset=utf-8",
+	"markdown": "text/markdown; charset=utf-8",
+	"text":     "text/plain; charset=utf-8",
+	"json":     "application/json",
+	"slack":    "application/json",
+	"teams":    "application/json",
+	"junit":    "application/xml",
+	"badge":    "image/svg+xml",
+}
+
+// uploadOutputs uploads every written output to the configured bucket and
+// returns the URL of the report: the first html output when there is one,
+// else the first output, presigned when s3_presign is on.
+func uploadOutputs(config Config, pipeline models.Pipeline, outputs *outputSet, specs []outputSpec, sender *notify.Sender) (string, error) {
+	bucket := storage.S3{
+		Endpoint:     config.S3Endpoint,
+		Region:       config.S3Region,
+		Bucket:       config.S3Bucket,
+		AccessKey:    config.S3AccessKey,
+		SecretKey:    config.S3SecretKey,
+		SessionToken: config.S3SessionToken,
+		PathStyle:    config.S3PathStyle,
+	}
+	var expiry time.Duration
+	if config.S3Presign {
+		var err error
+		expiry, err = time.ParseDuration(config.S3PresignExpiry)
+		if err != nil {
+			return "", fmt.Errorf("invalid s3_presign_expiry %q: %w", config.S3PresignExpiry, err)
+		}
+	}
+	pattern := config.S3Key
+	if pattern == "" {
+		pattern = defaultS3Key
+	}
+
+	var reportURL string
+	reportIsHTML := false
+	for _, spec := range specs {
+		content, err := outputs.render(spec.Format)
+		if err != nil {
+			return "", err
+		}
+		key := s3Key(pattern, spec, pipeline)
+		contentType, ok := contentTypes[spec.Format]
+		if !ok {
+			contentType = "application/octet-stream"
+		}
+		request, err := bucket.PutObject(key, content, contentType, time.Now())
+		if err != nil {
+			return "", err
+		}
+		upload := notify.Request{Method: request.Method, URL: request.URL.String(), Header: request.Header, Body: content}
+		if _, err := sender.Do("s3 upload of "+key, upload); err != nil {
+			return "", err
+		}
+
+		objectURL, err := bucket.ObjectURL(key)
+		if err != nil {
+			return "", err
+		}
+		url := objectURL.String()
+		if config.S3Presign {
+			url, err = bucket.PresignGet(key, expiry, time.Now())
+			if err != nil {
+				return "", err
+			}
+		}
+		fmt.Printf("| \033[1;36mPipeline %s output uploaded to\033[0m \033[1;32ms3://%s/%s\033[0m\n", spec.Format, config.S3Bucket, key)
+		if reportURL == "" || (spec.Format == "html" && !reportIsHTML) {
+			reportURL = url
+			reportIsHTML = spec.Format == "html"
+		}
+	}
+	return reportURL, nil
+}
+
+// s3Key expands the key pattern of an output. {file} is the name of the
+// written file, or index.html for the html output.
+func s3Key(pattern string, spec outputSpec, pipeline models.Pipeline) string {
+	file := filepath.Base(spec.Path)
+	if spec.Format == "html" {
+		file = "index.html"
+	}
+	key := strings.ReplaceAll(pattern, "{file}", file)
+	return strings.TrimPrefix(expandOutputPattern(key, spec.Format, pipeline), "/")
+}